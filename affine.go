@@ -208,3 +208,55 @@ func (a *Affine2D) RotateCurveAboutPoint(curve Curve, angle float64, rotationPoi
 	}
 	return result
 }
+
+// TransformEllipse applies the affine transformation to an ellipse. Affine maps take
+// ellipses to ellipses, so the result is exact rather than a list of points.
+func (a *Affine2D) TransformEllipse(e Ellipse) Ellipse {
+	out, _, _ := a.transformEllipse(e)
+	return out
+}
+
+// TransformCircle applies the affine transformation to a circle. A non-uniform scale or
+// a shear turns it into an ellipse.
+func (a *Affine2D) TransformCircle(c Circle) Ellipse {
+	return a.TransformEllipse(c.ToEllipse())
+}
+
+// TransformArc applies the affine transformation to an arc. A transformation that
+// mirrors the plane reverses the direction of the sweep.
+func (a *Affine2D) TransformArc(arc Arc) Arc {
+	e, shift, mirror := a.transformEllipse(arc.Ellipse)
+	if mirror {
+		return Arc{Ellipse: e, Start: -(arc.Start + shift), Sweep: -arc.Sweep}
+	}
+	return Arc{Ellipse: e, Start: arc.Start + shift, Sweep: arc.Sweep}
+}
+
+// transformEllipse returns the transformed ellipse along with how eccentric angles map
+// onto it: the point at angle t goes to the point at angle t+shift, or -(t+shift) when
+// mirror is set
+func (a *Affine2D) transformEllipse(e Ellipse) (out Ellipse, shift float64, mirror bool) {
+	// the ellipse is the unit circle under the linear map M = L * R(angle) * diag(rx, ry),
+	// with L the linear part of the transformation
+	sin, cos := math.Sincos(e.Angle)
+	m00 := (a.a*cos + a.b*sin) * e.Rx
+	m01 := (-a.a*sin + a.b*cos) * e.Ry
+	m10 := (a.d*cos + a.e*sin) * e.Rx
+	m11 := (-a.d*sin + a.e*cos) * e.Ry
+	// closed form singular value decomposition M = R(phi) * diag(sx, sy) * R(theta)
+	p := (m00 + m11) / 2
+	q := (m00 - m11) / 2
+	r := (m10 + m01) / 2
+	s := (m10 - m01) / 2
+	hypot1, hypot2 := math.Hypot(p, s), math.Hypot(q, r)
+	sx, sy := hypot1+hypot2, hypot1-hypot2
+	a1, a2 := math.Atan2(r, q), math.Atan2(s, p)
+	theta, phi := (a2-a1)/2, (a2+a1)/2
+	out = Ellipse{
+		Center: a.TransformPoint(e.Center),
+		Rx:     sx,
+		Ry:     math.Abs(sy),
+		Angle:  phi,
+	}
+	return out, theta, sy < 0
+}
@@ -233,3 +233,28 @@ func TestAffine2D_RotateCurveAboutPoint(t *testing.T) {
 	assert.True(Equalf(0.5-math.Sqrt2/2, result.Points[3].X))
 	assert.True(Equalf(0.5, result.Points[3].Y))
 }
+
+func TestAffine2D_TransformEllipse(t *testing.T) {
+	assert := assert.New(t)
+	scale := NewAffine2DWithScale(3, 1)
+	c := Circle{Center: Point{X: 1, Y: 2}, Radius: 2}
+	e := scale.TransformCircle(c)
+	assert.InDelta(3, e.Center.X, Smol)
+	assert.InDelta(2, e.Center.Y, Smol)
+	assert.InDelta(6, math.Max(e.Rx, e.Ry), Smol)
+	assert.InDelta(2, math.Min(e.Rx, e.Ry), Smol)
+
+	// any mix of transformations maps the points of an arc onto the transformed arc
+	m := Mult(NewAffine2DWithTranslation(2, -1), Mult(NewAffine2DWithShear(0.4, 0), Mult(NewAffine2DWithRotation(0.3), NewAffine2DWithScale(-1.5, 0.5))))
+	arc := Arc{Ellipse: Ellipse{Center: Point{X: 1, Y: 1}, Rx: 2, Ry: 1, Angle: 0.5}, Start: 0.3, Sweep: 2}
+	out := m.TransformArc(arc)
+	for i := 0; i <= 10; i++ {
+		u := arc.Start + arc.Sweep*float64(i)/10
+		v := out.Start + out.Sweep*float64(i)/10
+		want := m.TransformPoint(arc.PointAtAngle(u))
+		got := out.PointAtAngle(v)
+		assert.InDelta(want.X, got.X, 1e-9)
+		assert.InDelta(want.Y, got.Y, 1e-9)
+	}
+	assert.Less(out.Sweep, 0.0)
+}
@@ -0,0 +1,192 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// ArcLengthCurve is a view of a curve indexed by distance along it. The cumulative
+// length at every vertex is computed once, so positions, tangents, normals and
+// curvature at a given distance take O(log n) for a curve with n points, instead of
+// walking the whole curve like [Curve.Lerp] does.
+//
+// Distances run from 0 to Length(). For open curves they are clamped to that range,
+// for closed curves they wrap around.
+type ArcLengthCurve struct {
+	points []Point   // the vertices, repeating the first one at the end of a closed curve
+	cum    []float64 // cum[i] is the distance from the start to points[i]
+	closed bool
+}
+
+// NewArcLengthCurve builds an arc-length view of c. Later changes to c are not seen by
+// the view. Consecutive repeated points are ignored.
+func NewArcLengthCurve(c Curve) *ArcLengthCurve {
+	pts := dedupeConsecutivePoints(c.Points)
+	closed := c.Closed
+	if closed && len(pts) > 1 {
+		if pts[0].IsEqual(pts[len(pts)-1]) {
+			pts = pts[:len(pts)-1]
+		}
+		pts = append(pts, pts[0])
+	}
+	cum := make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		cum[i] = cum[i-1] + Distance(pts[i-1], pts[i])
+	}
+	return &ArcLengthCurve{points: pts, cum: cum, closed: closed}
+}
+
+// Length returns the total length of the curve
+func (a *ArcLengthCurve) Length() float64 {
+	if len(a.cum) == 0 {
+		return 0
+	}
+	return a.cum[len(a.cum)-1]
+}
+
+// locate returns the segment i (from points[i] to points[i+1]) containing distance d
+// and the fraction of the way along it
+func (a *ArcLengthCurve) locate(d float64) (int, float64) {
+	total := a.Length()
+	if a.closed && total > 0 {
+		d = math.Mod(d, total)
+		if d < 0 {
+			d += total
+		}
+	}
+	d = Clamp(0, total, d)
+	// the last segment whose start is at or before d
+	i := sort.Search(len(a.cum), func(k int) bool { return a.cum[k] > d }) - 1
+	if i >= len(a.points)-1 {
+		i = len(a.points) - 2
+	}
+	if i < 0 {
+		i = 0
+	}
+	seg := a.cum[i+1] - a.cum[i]
+	return i, Clamp(0, 1, (d-a.cum[i])/seg)
+}
+
+// PointAt returns the point at distance d along the curve
+func (a *ArcLengthCurve) PointAt(d float64) Point {
+	switch len(a.points) {
+	case 0:
+		return Point{}
+	case 1:
+		return a.points[0]
+	}
+	i, t := a.locate(d)
+	return a.points[i].Lerp(a.points[i+1], t)
+}
+
+// Lerp returns the point a given percentage (between 0 and 1) along the curve
+func (a *ArcLengthCurve) Lerp(percentage float64) Point {
+	return a.PointAt(percentage * a.Length())
+}
+
+// TangentAt returns the unit direction of the curve at distance d. At a vertex the
+// direction of the segment leaving it is used (or of the last segment, at the end of
+// an open curve). A curve with fewer than two distinct points has no tangent and
+// gives the zero vector.
+func (a *ArcLengthCurve) TangentAt(d float64) Vec2 {
+	if len(a.points) < 2 {
+		return Vec2{}
+	}
+	i, _ := a.locate(d)
+	return Vec2FromPoints(a.points[i], a.points[i+1]).Normalize()
+}
+
+// NormalAt returns the unit normal of the curve at distance d, which is the tangent
+// rotated a quarter turn counterclockwise (to the left of the direction of travel)
+func (a *ArcLengthCurve) NormalAt(d float64) Vec2 {
+	t := a.TangentAt(d)
+	return Vec2{X: -t.Y, Y: t.X}
+}
+
+// CurvatureAt estimates the signed curvature at distance d from the circle through
+// the points one mean segment length before and after it. The curvature is positive
+// where the curve turns left (towards NormalAt) and is 1/r on a curve sampled evenly
+// from a circle of radius r.
+func (a *ArcLengthCurve) CurvatureAt(d float64) float64 {
+	n := len(a.points)
+	if n < 3 {
+		return 0
+	}
+	h := a.Length() / float64(n-1)
+	before, after := d-h, d+h
+	if !a.closed {
+		before = math.Max(before, 0)
+		after = math.Min(after, a.Length())
+	}
+	p, q, r := a.PointAt(before), a.PointAt(d), a.PointAt(after)
+	denom := Distance(p, q) * Distance(q, r) * Distance(r, p)
+	if denom == 0 {
+		return 0
+	}
+	return 2 * orient2(p, q, r) / denom
+}
+
+// ResampleCount returns a curve with n points spread evenly by arc length. An open
+// curve keeps both of its end points; a closed curve gets n points with equal gaps
+// all the way round, starting at its first point.
+func (a *ArcLengthCurve) ResampleCount(n int) Curve {
+	out := Curve{Closed: a.closed}
+	if n <= 0 || len(a.points) == 0 {
+		return out
+	}
+	total := a.Length()
+	steps := n - 1
+	if a.closed {
+		steps = n
+	}
+	out.Points = make([]Point, n)
+	for i := range out.Points {
+		d := 0.0
+		if steps > 0 {
+			d = total * float64(i) / float64(steps)
+		}
+		out.Points[i] = a.PointAt(d)
+	}
+	return out
+}
+
+// ResampleSpacing returns a curve with points exactly the given arc length apart,
+// starting at the first point. An open curve also keeps its end point, so its last
+// gap may be shorter; likewise the closing gap of a closed curve. A curve of zero
+// length gives just its first point.
+func (a *ArcLengthCurve) ResampleSpacing(spacing float64) Curve {
+	out := Curve{Closed: a.closed}
+	if spacing <= 0 || len(a.points) == 0 {
+		return out
+	}
+	total := a.Length()
+	if total == 0 {
+		// all the points coincide
+		out.Points = []Point{a.points[0]}
+		return out
+	}
+	count := int(math.Floor(total / spacing))
+	if float64(count)*spacing >= total-Smol*spacing {
+		// the last step lands on (or next to) the end point
+		count--
+	}
+	for i := 0; i <= count; i++ {
+		out.Points = append(out.Points, a.PointAt(float64(i)*spacing))
+	}
+	if !a.closed {
+		out.Points = append(out.Points, a.points[len(a.points)-1])
+	}
+	return out
+}
+
+// Resample returns a copy of the curve with points spaced evenly by arc length, see
+// [ArcLengthCurve.ResampleSpacing]
+func (c *Curve) Resample(spacing float64) Curve {
+	return NewArcLengthCurve(*c).ResampleSpacing(spacing)
+}
+
+// ResampleCount returns a copy of the curve with n points spaced evenly by arc
+// length, see [ArcLengthCurve.ResampleCount]
+func (c *Curve) ResampleCount(n int) Curve {
+	return NewArcLengthCurve(*c).ResampleCount(n)
+}
@@ -0,0 +1,111 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArcLengthCurve_matchesLerp(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	for _, closed := range []bool{false, true} {
+		var c Curve
+		c.Closed = closed
+		for i := 0; i < 50; i++ {
+			c.AddPoint(rng.Float64(), rng.Float64())
+		}
+		a := NewArcLengthCurve(c)
+		assert.InDelta(t, c.Length(), a.Length(), 1e-9)
+		for k := 1; k < 100; k++ {
+			pct := float64(k) / 100
+			want := c.Lerp(pct)
+			got := a.Lerp(pct)
+			assert.InDelta(t, want.X, got.X, 1e-9)
+			assert.InDelta(t, want.Y, got.Y, 1e-9)
+		}
+	}
+}
+
+func TestArcLengthCurve_ends(t *testing.T) {
+	c := Curve{Points: []Point{{0, 0}, {1, 0}, {1, 0}, {1, 2}}}
+	a := NewArcLengthCurve(c)
+	assert.Equal(t, 3.0, a.Length())
+	assert.Equal(t, Point{0, 0}, a.PointAt(-1))
+	assert.Equal(t, Point{1, 2}, a.PointAt(10))
+	assert.Equal(t, Point{1, 0.5}, a.PointAt(1.5))
+	assert.Equal(t, Vec2{X: 1, Y: 0}, a.TangentAt(0.5))
+	assert.Equal(t, Vec2{X: 0, Y: 1}, a.TangentAt(1), "the segment leaving a vertex")
+	assert.Equal(t, Vec2{X: 0, Y: 1}, a.TangentAt(3))
+	assert.Equal(t, Vec2{X: -1, Y: 0}, a.NormalAt(2))
+
+	c.Closed = true
+	a = NewArcLengthCurve(c)
+	assert.InDelta(t, 3+math.Sqrt(5), a.Length(), 1e-12)
+	p := a.PointAt(a.Length() + 0.5)
+	assert.InDelta(t, 0.5, p.X, 1e-12)
+	assert.InDelta(t, 0, p.Y, 1e-12)
+
+	assert.Equal(t, Point{}, NewArcLengthCurve(Curve{}).PointAt(1))
+	assert.Equal(t, Vec2{}, NewArcLengthCurve(Curve{Points: []Point{{1, 1}}}).TangentAt(0))
+}
+
+func TestArcLengthCurve_circle(t *testing.T) {
+	r := 2.0
+	a := NewArcLengthCurve(Circle{Center: Point{X: 1, Y: 1}, Radius: r}.ToCurve(360))
+	for k := 0; k < 20; k++ {
+		d := a.Length() * float64(k) / 20
+		assert.InDelta(t, 1/r, a.CurvatureAt(d), 1e-3)
+		// the normal of a counterclockwise circle points at its center
+		p := a.PointAt(d)
+		n := a.NormalAt(d)
+		toCenter := Vec2FromPoints(p, Point{X: 1, Y: 1}).Normalize()
+		assert.InDelta(t, 1, n.Dot(toCenter), 1e-3)
+	}
+	straight := NewArcLengthCurve(Curve{Points: []Point{{0, 0}, {1, 0}, {2, 0}}})
+	assert.Equal(t, 0.0, straight.CurvatureAt(1))
+}
+
+func TestArcLengthCurve_resample(t *testing.T) {
+	c := Curve{Points: []Point{{0, 0}, {3, 0}, {3, 1.5}}}
+	got := c.Resample(1)
+	require.Len(t, got.Points, 6)
+	assert.Equal(t, Point{3, 1.5}, got.Points[5])
+	for i := 1; i < 4; i++ {
+		assert.InDelta(t, 1, Distance(got.Points[i-1], got.Points[i]), 1e-12)
+	}
+
+	got = c.ResampleCount(10)
+	require.Len(t, got.Points, 10)
+	assert.Equal(t, Point{0, 0}, got.Points[0])
+	assert.Equal(t, Point{3, 1.5}, got.Points[9])
+	assert.InDelta(t, 4.5, got.Length(), 1e-9)
+
+	sq := Rect{X: 0, Y: 0, W: 1, H: 1}.ToCurve()
+	got = sq.Resample(0.25)
+	assert.True(t, got.Closed)
+	require.Len(t, got.Points, 16)
+	n := len(got.Points)
+	for i := range got.Points {
+		assert.InDelta(t, 0.25, Distance(got.Points[i], got.Points[(i+1)%n]), 1e-12)
+	}
+	got = sq.ResampleCount(8)
+	require.Len(t, got.Points, 8)
+	assert.InDelta(t, 0.5, Distance(got.Points[7], got.Points[0]), 1e-12)
+}
+
+func TestArcLengthCurve_resampleZeroLength(t *testing.T) {
+	for _, closed := range []bool{false, true} {
+		c := Curve{Closed: closed, Points: []Point{{2, 1}, {2, 1}, {2, 1}}}
+		got := c.Resample(0.5)
+		assert.Equal(t, closed, got.Closed)
+		assert.Equal(t, []Point{{2, 1}}, got.Points)
+		for _, p := range c.ResampleCount(3).Points {
+			assert.Equal(t, Point{2, 1}, p)
+		}
+	}
+	single := Curve{Points: []Point{{4, 4}}}
+	assert.Equal(t, []Point{{4, 4}}, single.Resample(1).Points)
+}
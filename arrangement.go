@@ -0,0 +1,189 @@
+package gaul
+
+import "sort"
+
+// Arrangement is the planar subdivision formed by a set of overlapping lines and
+// curves: every crossing becomes a vertex, every segment is split at the vertices on
+// it, and the bounded regions enclosed by the resulting edges become faces.
+type Arrangement struct {
+	// Vertices holds the end points and intersection points of the input
+	Vertices []Point
+	// Edges are pairs of indices into Vertices. No two edges cross or overlap.
+	Edges [][2]int
+	// Faces are the boundaries of the bounded faces, wound counterclockwise
+	Faces []Curve
+	// Holes[i] holds the clockwise boundaries of pieces of the arrangement that lie
+	// inside face i without touching its boundary
+	Holes [][]Curve
+	// Adjacency[i] lists, in increasing order, the faces sharing an edge with face i
+	Adjacency [][]int
+}
+
+// NewArrangement builds the planar arrangement of the given lines and curves. Open
+// curves contribute their segments only; closed curves also contribute the segment
+// back to their first point. Edges that don't enclose anything (loose ends of lines,
+// for example) appear in Edges but don't affect Faces.
+func NewArrangement(lines []Line, curves []Curve) *Arrangement {
+	var segs []taggedSegment
+	for _, l := range lines {
+		segs = append(segs, taggedSegment{Line: l})
+	}
+	for _, c := range curves {
+		pts := dedupeConsecutivePoints(c.Points)
+		n := len(pts)
+		count := n - 1
+		if c.Closed && n > 2 {
+			count = n
+		}
+		for i := 0; i < count; i++ {
+			segs = append(segs, taggedSegment{Line: Line{P: pts[i], Q: pts[(i+1)%n]}})
+		}
+	}
+	arr := &Arrangement{}
+	if len(segs) == 0 {
+		return arr
+	}
+	g := newPlanarGraph(segs)
+	arr.Vertices = g.pts
+	arr.Edges = make([][2]int, len(g.edges))
+	for i, e := range g.edges {
+		arr.Edges[i] = [2]int{e.a, e.b}
+	}
+
+	// Walking both directions of every edge that lies on a cycle traces each face with
+	// the face on its left: bounded faces come out counterclockwise, and the outer
+	// boundary of each connected piece comes out clockwise.
+	var directed [][2]int
+	for i, keep := range cycleEdges(len(g.pts), arr.Edges) {
+		if keep {
+			e := arr.Edges[i]
+			directed = append(directed, e, [2]int{e[1], e[0]})
+		}
+	}
+	loops := traceLoops(g.pts, directed)
+	polys := make([][]Point, len(loops))
+	areas := make([]float64, len(loops))
+	loopFace := make([]int, len(loops))
+	var outer []int
+	for i, loop := range loops {
+		polys[i] = make([]Point, len(loop))
+		for j, v := range loop {
+			polys[i][j] = g.pts[v]
+		}
+		areas[i] = polygonSignedArea(polys[i])
+		loopFace[i] = -1
+		if areas[i] > 0 {
+			loopFace[i] = len(arr.Faces)
+			arr.Faces = append(arr.Faces, Curve{Points: removeCollinearPoints(polys[i]), Closed: true})
+		} else if areas[i] < 0 {
+			outer = append(outer, i)
+		}
+	}
+	arr.Holes = make([][]Curve, len(arr.Faces))
+	arr.Adjacency = make([][]int, len(arr.Faces))
+
+	// a piece nested in another lies in the smallest face of another piece around one
+	// of its vertices
+	component := edgeComponents(len(g.pts), directed)
+	for _, i := range outer {
+		best := -1
+		for j := range loops {
+			if areas[j] <= 0 || (best >= 0 && areas[j] >= areas[best]) {
+				continue
+			}
+			if component[loops[j][0]] == component[loops[i][0]] {
+				continue
+			}
+			if polygonWinding(polys[j], polys[i][0]) != 0 {
+				best = j
+			}
+		}
+		if best >= 0 {
+			loopFace[i] = loopFace[best]
+			hole := Curve{Points: removeCollinearPoints(polys[i]), Closed: true}
+			arr.Holes[loopFace[best]] = append(arr.Holes[loopFace[best]], hole)
+		}
+	}
+
+	leftOf := make(map[[2]int]int)
+	for i, loop := range loops {
+		for j, v := range loop {
+			leftOf[[2]int{v, loop[(j+1)%len(loop)]}] = loopFace[i]
+		}
+	}
+	seen := make(map[[2]int]bool)
+	for _, e := range directed {
+		f1, ok1 := leftOf[e]
+		f2, ok2 := leftOf[[2]int{e[1], e[0]}]
+		if !ok1 || !ok2 || f1 < 0 || f2 < 0 || f1 >= f2 || seen[[2]int{f1, f2}] {
+			continue
+		}
+		seen[[2]int{f1, f2}] = true
+		arr.Adjacency[f1] = append(arr.Adjacency[f1], f2)
+		arr.Adjacency[f2] = append(arr.Adjacency[f2], f1)
+	}
+	for _, adj := range arr.Adjacency {
+		sort.Ints(adj)
+	}
+	return arr
+}
+
+// cycleEdges marks the edges that lie on a cycle, by repeatedly pruning edges that
+// end at a vertex of degree one
+func cycleEdges(nVerts int, edges [][2]int) []bool {
+	keep := make([]bool, len(edges))
+	degree := make([]int, nVerts)
+	incident := make([][]int, nVerts)
+	for i, e := range edges {
+		keep[i] = true
+		for _, v := range e {
+			degree[v]++
+			incident[v] = append(incident[v], i)
+		}
+	}
+	var stack []int
+	for v, d := range degree {
+		if d == 1 {
+			stack = append(stack, v)
+		}
+	}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, i := range incident[v] {
+			if !keep[i] {
+				continue
+			}
+			keep[i] = false
+			for _, w := range edges[i] {
+				degree[w]--
+				if degree[w] == 1 {
+					stack = append(stack, w)
+				}
+			}
+		}
+	}
+	return keep
+}
+
+// edgeComponents labels each vertex with the connected component it belongs to
+func edgeComponents(nVerts int, edges [][2]int) []int {
+	parent := make([]int, nVerts)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+	for _, e := range edges {
+		parent[find(e[0])] = find(e[1])
+	}
+	for i := range parent {
+		parent[i] = find(i)
+	}
+	return parent
+}
@@ -0,0 +1,113 @@
+package gaul
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func faceArea(a *Arrangement, i int) float64 {
+	area := a.Faces[i].Area()
+	for _, h := range a.Holes[i] {
+		area -= h.Area()
+	}
+	return area
+}
+
+func TestNewArrangement_overlappingSquares(t *testing.T) {
+	arr := NewArrangement(nil, []Curve{square(0, 0, 2), square(1, 1, 2)})
+	require.Len(t, arr.Faces, 3)
+	var total float64
+	middle := -1
+	for i, f := range arr.Faces {
+		assert.Greater(t, f.SignedArea(), 0.0)
+		total += faceArea(arr, i)
+		if math.Abs(f.Area()-1) < 1e-9 && len(f.Points) == 4 {
+			middle = i
+		}
+	}
+	assert.InDelta(t, 7, total, 1e-9)
+	require.GreaterOrEqual(t, middle, 0)
+	assert.Len(t, arr.Adjacency[middle], 2)
+	assert.Len(t, arr.Vertices, 10)
+}
+
+func TestNewArrangement_grid(t *testing.T) {
+	// a tic-tac-toe board in a frame, with the lines sticking out of the frame
+	var lines []Line
+	for _, f := range []float64{1, 2} {
+		lines = append(lines,
+			Line{P: Point{-1, f}, Q: Point{4, f}},
+			Line{P: Point{f, -1}, Q: Point{f, 4}},
+		)
+	}
+	arr := NewArrangement(lines, []Curve{square(0, 0, 3)})
+	require.Len(t, arr.Faces, 9)
+	degrees := map[int]int{}
+	for i := range arr.Faces {
+		assert.InDelta(t, 1, faceArea(arr, i), 1e-9)
+		degrees[len(arr.Adjacency[i])]++
+	}
+	assert.Equal(t, map[int]int{2: 4, 3: 4, 4: 1}, degrees)
+}
+
+func TestNewArrangement_danglingLines(t *testing.T) {
+	lines := []Line{
+		{P: Point{0, 0}, Q: Point{4, 0}},
+		{P: Point{1, -1}, Q: Point{1, 3}},
+		{P: Point{3, -1}, Q: Point{3, 3}},
+	}
+	arr := NewArrangement(lines, nil)
+	assert.Empty(t, arr.Faces)
+	assert.Len(t, arr.Edges, 7)
+}
+
+func TestNewArrangement_nested(t *testing.T) {
+	outer := Circle{Center: Point{}, Radius: 2}.ToCurve(64)
+	inner := square(-0.5, -0.5, 1)
+	arr := NewArrangement(nil, []Curve{outer, inner})
+	require.Len(t, arr.Faces, 2)
+	holes := 0
+	for i := range arr.Faces {
+		for _, h := range arr.Holes[i] {
+			holes++
+			assert.Less(t, h.SignedArea(), 0.0)
+			assert.InDelta(t, 1, h.Area(), 1e-9)
+		}
+	}
+	assert.Equal(t, 1, holes)
+	assert.Equal(t, []int{1}, arr.Adjacency[0], "the ring borders the face filling its hole")
+	assert.Equal(t, []int{0}, arr.Adjacency[1])
+}
+
+func TestNewArrangement_lissajous(t *testing.T) {
+	curve := GenLissajous(Lissajous{Nx: 3, Ny: 2, Px: Pi / 2}, 400, Point{}, 1)
+	curve.Closed = true
+	arr := NewArrangement(nil, []Curve{curve})
+	require.NotEmpty(t, arr.Faces)
+
+	// Euler's formula for a connected plane graph, counting the unbounded face
+	assert.Equal(t, 2, len(arr.Vertices)-len(arr.Edges)+len(arr.Faces)+1)
+
+	// faces tile the region they cover without overlapping
+	var total float64
+	for i := range arr.Faces {
+		total += faceArea(arr, i)
+	}
+	union := BooleanCurves(arr.Faces, nil, BoolUnion, NonZero)
+	assert.InDelta(t, signedAreaSum(union), total, 1e-9)
+
+	for i, adj := range arr.Adjacency {
+		for _, j := range adj {
+			assert.Contains(t, arr.Adjacency[j], i)
+		}
+	}
+}
+
+func TestNewArrangement_empty(t *testing.T) {
+	arr := NewArrangement(nil, nil)
+	assert.Empty(t, arr.Faces)
+	assert.Empty(t, arr.Vertices)
+}
@@ -0,0 +1,591 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// FillRule decides which points are inside a set of closed curves, given the winding
+// number of the point with respect to all of them. Options that take a region as
+// several curves, outer boundaries and holes, use it to tell the two apart.
+type FillRule int
+
+const (
+	// EvenOdd treats a point as inside when its winding number is odd. Orientation of
+	// the input curves does not matter, so holes may be listed in any direction.
+	EvenOdd FillRule = iota
+	// NonZero treats a point as inside when its winding number is not zero. Holes must
+	// be wound opposite to the curve that contains them.
+	NonZero
+	// Positive treats a point as inside when its winding number is greater than zero,
+	// so clockwise curves only ever remove area. It is mainly useful for oriented
+	// input such as raw offset outlines.
+	Positive
+)
+
+// inside applies the fill rule to a winding number
+func (r FillRule) inside(w int) bool {
+	switch r {
+	case NonZero:
+		return w != 0
+	case Positive:
+		return w > 0
+	}
+	return w%2 != 0
+}
+
+// BoolOp selects the set operation performed by [BooleanCurves]
+type BoolOp int
+
+const (
+	BoolUnion BoolOp = iota
+	BoolIntersection
+	BoolDifference
+	BoolXor
+)
+
+func (op BoolOp) apply(inA, inB bool) bool {
+	switch op {
+	case BoolUnion:
+		return inA || inB
+	case BoolIntersection:
+		return inA && inB
+	case BoolDifference:
+		return inA && !inB
+	case BoolXor:
+		return inA != inB
+	}
+	return false
+}
+
+// CurveUnion returns the region covered by a or b as a set of closed curves
+func CurveUnion(a, b Curve) []Curve {
+	return BooleanCurves([]Curve{a}, []Curve{b}, BoolUnion, NonZero)
+}
+
+// CurveIntersection returns the region covered by both a and b as a set of closed curves
+func CurveIntersection(a, b Curve) []Curve {
+	return BooleanCurves([]Curve{a}, []Curve{b}, BoolIntersection, NonZero)
+}
+
+// CurveDifference returns the region covered by a but not by b as a set of closed curves
+func CurveDifference(a, b Curve) []Curve {
+	return BooleanCurves([]Curve{a}, []Curve{b}, BoolDifference, NonZero)
+}
+
+// CurveXor returns the region covered by exactly one of a and b as a set of closed curves
+func CurveXor(a, b Curve) []Curve {
+	return BooleanCurves([]Curve{a}, []Curve{b}, BoolXor, NonZero)
+}
+
+// BooleanCurves computes a boolean operation between two regions. Each region is
+// described by a set of curves (outer boundaries and holes) interpreted with the
+// given fill rule; every curve is treated as closed regardless of its Closed flag.
+// Inputs may be non-convex, self-intersecting or touch themselves and each other.
+//
+// The result is a set of closed, simple curves with no repeated end point. Outer
+// boundaries are counterclockwise and holes are clockwise (for +Y up), so the result
+// can be fed back into BooleanCurves with either fill rule. Collinear vertices are
+// removed and regions that only touch at a vertex are returned as separate curves.
+func BooleanCurves(subject, clip []Curve, op BoolOp, rule FillRule) []Curve {
+	var segs []taggedSegment
+	segs = appendCurveSegments(segs, subject, 0)
+	segs = appendCurveSegments(segs, clip, 1)
+	if len(segs) == 0 {
+		return nil
+	}
+	g := newPlanarGraph(segs)
+	idx := newWindingIndex(g)
+
+	var directed [][2]int
+	for e := range g.edges {
+		inLeft, inRight := [2]bool{}, [2]bool{}
+		for set := 0; set < 2; set++ {
+			l, r := idx.sideWindings(e, set)
+			inLeft[set] = rule.inside(l)
+			inRight[set] = rule.inside(r)
+		}
+		left := op.apply(inLeft[0], inLeft[1])
+		right := op.apply(inRight[0], inRight[1])
+		if left == right {
+			continue
+		}
+		edge := g.edges[e]
+		if left {
+			directed = append(directed, [2]int{edge.a, edge.b})
+		} else {
+			directed = append(directed, [2]int{edge.b, edge.a})
+		}
+	}
+	return loopsToCurves(g.pts, traceLoops(g.pts, directed))
+}
+
+// taggedSegment is an input segment labelled with the region (set) it came from. The
+// direction P->Q matters for winding numbers.
+type taggedSegment struct {
+	Line
+	set int
+}
+
+func appendCurveSegments(segs []taggedSegment, curves []Curve, set int) []taggedSegment {
+	for _, c := range curves {
+		pts := dedupeConsecutivePoints(c.Points)
+		if len(pts) > 1 && pts[0].IsEqual(pts[len(pts)-1]) {
+			pts = pts[:len(pts)-1]
+		}
+		n := len(pts)
+		if n < 2 {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			segs = append(segs, taggedSegment{Line: Line{P: pts[i], Q: pts[(i+1)%n]}, set: set})
+		}
+	}
+	return segs
+}
+
+// dedupeConsecutivePoints removes consecutive repeated points from a polyline
+func dedupeConsecutivePoints(pts []Point) []Point {
+	out := make([]Point, 0, len(pts))
+	for _, p := range pts {
+		if len(out) > 0 && out[len(out)-1].IsEqual(p) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// planarEdge is an undirected edge of a planar graph, stored in the canonical
+// direction a->b with a < b. delta counts, per input set, how many input segments run
+// a->b minus how many run b->a.
+type planarEdge struct {
+	a, b  int
+	delta [2]int
+}
+
+// planarGraph is the result of splitting a set of segments at all of their mutual
+// intersections. Vertices closer than eps are merged and coincident edges are
+// combined, so no two edges cross or overlap except at shared vertices.
+type planarGraph struct {
+	pts   []Point
+	edges []planarEdge
+	eps   float64
+}
+
+func newPlanarGraph(segs []taggedSegment) *planarGraph {
+	eps := segmentsTolerance(segs)
+	splits := make([][]Point, len(segs))
+	for i, s := range segs {
+		splits[i] = []Point{s.P, s.Q}
+	}
+	forEachCandidatePair(segs, eps, func(i, j int) {
+		pts, n := segmentIntersections(segs[i].P, segs[i].Q, segs[j].P, segs[j].Q, eps)
+		for k := 0; k < n; k++ {
+			splits[i] = append(splits[i], pts[k])
+			splits[j] = append(splits[j], pts[k])
+		}
+	})
+
+	g := &planarGraph{eps: eps}
+	vi := newVertexIndex(eps)
+	edgeIndex := make(map[[2]int]int)
+	for i, s := range segs {
+		sp := splits[i]
+		d := Vec2FromPoints(s.P, s.Q)
+		sort.Slice(sp, func(x, y int) bool {
+			return Vec2FromPoints(s.P, sp[x]).Dot(d) < Vec2FromPoints(s.P, sp[y]).Dot(d)
+		})
+		prev := vi.add(sp[0])
+		for _, p := range sp[1:] {
+			cur := vi.add(p)
+			if cur == prev {
+				continue
+			}
+			a, b, dir := prev, cur, 1
+			if a > b {
+				a, b, dir = b, a, -1
+			}
+			e, ok := edgeIndex[[2]int{a, b}]
+			if !ok {
+				e = len(g.edges)
+				edgeIndex[[2]int{a, b}] = e
+				g.edges = append(g.edges, planarEdge{a: a, b: b})
+			}
+			g.edges[e].delta[s.set] += dir
+			prev = cur
+		}
+	}
+	g.pts = vi.pts
+	return g
+}
+
+// segmentsTolerance returns a snapping distance relative to the extent of the input
+func segmentsTolerance(segs []taggedSegment) float64 {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, s := range segs {
+		for _, p := range []Point{s.P, s.Q} {
+			minX = math.Min(minX, p.X)
+			minY = math.Min(minY, p.Y)
+			maxX = math.Max(maxX, p.X)
+			maxY = math.Max(maxY, p.Y)
+		}
+	}
+	scale := math.Max(maxX-minX, maxY-minY)
+	if scale == 0 || math.IsInf(scale, 0) || math.IsNaN(scale) {
+		scale = 1
+	}
+	return scale * 1e-10
+}
+
+// forEachCandidatePair calls fn for each pair of segments whose bounding boxes overlap
+// (within eps), using a sweep over the minimum x coordinate.
+func forEachCandidatePair(segs []taggedSegment, eps float64, fn func(i, j int)) {
+	n := len(segs)
+	boxes := make([]Rect, n)
+	order := make([]int, n)
+	for i, s := range segs {
+		boxes[i] = s.Boundary()
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return boxes[order[a]].X < boxes[order[b]].X })
+	for oi, i := range order {
+		bi := boxes[i]
+		for _, j := range order[oi+1:] {
+			bj := boxes[j]
+			if bj.X > bi.X+bi.W+eps {
+				break
+			}
+			if bj.Y > bi.Y+bi.H+eps || bi.Y > bj.Y+bj.H+eps {
+				continue
+			}
+			fn(i, j)
+		}
+	}
+}
+
+// segmentIntersections returns the points shared by the closed segments p1-p2 and
+// q1-q2: none, a single crossing or touching point, or the two end points of a
+// collinear overlap. Points within eps of a segment end point are snapped to it.
+func segmentIntersections(p1, p2, q1, q2 Point, eps float64) ([2]Point, int) {
+	var out [2]Point
+	r := Vec2FromPoints(p1, p2)
+	s := Vec2FromPoints(q1, q2)
+	rr := r.Dot(r)
+	ss := s.Dot(s)
+	if rr == 0 || ss == 0 {
+		return out, 0
+	}
+	qp := Vec2FromPoints(p1, q1)
+	denom := r.X*s.Y - r.Y*s.X
+	lr := math.Sqrt(rr)
+	ls := math.Sqrt(ss)
+
+	// distances of q's end points from the line through p
+	dq1 := (r.X*qp.Y - r.Y*qp.X) / lr
+	dq2 := (r.X*(q2.Y-p1.Y) - r.Y*(q2.X-p1.X)) / lr
+	if math.Abs(dq1) <= eps && math.Abs(dq2) <= eps {
+		// collinear: intersect the parameter intervals along p
+		t0 := qp.Dot(r) / rr
+		t1 := Vec2FromPoints(p1, q2).Dot(r) / rr
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		te := eps / lr
+		lo := math.Max(0, t0)
+		hi := math.Min(1, t1)
+		if lo > hi+te {
+			return out, 0
+		}
+		out[0] = collinearPoint(p1, p2, q1, q2, lo, te)
+		if hi-lo <= te {
+			return out, 1
+		}
+		out[1] = collinearPoint(p1, p2, q1, q2, hi, te)
+		return out, 2
+	}
+	if denom == 0 {
+		return out, 0
+	}
+	t := (qp.X*s.Y - qp.Y*s.X) / denom
+	u := (qp.X*r.Y - qp.Y*r.X) / denom
+	te := eps / lr
+	ue := eps / ls
+	if t < -te || t > 1+te || u < -ue || u > 1+ue {
+		return out, 0
+	}
+	switch {
+	case math.Abs(t) <= te:
+		out[0] = p1
+	case math.Abs(t-1) <= te:
+		out[0] = p2
+	case math.Abs(u) <= ue:
+		out[0] = q1
+	case math.Abs(u-1) <= ue:
+		out[0] = q2
+	default:
+		out[0] = p1.Lerp(p2, t)
+	}
+	return out, 1
+}
+
+// collinearPoint returns the point at parameter t along p1-p2, preferring an exact
+// end point of either segment when one is within tolerance.
+func collinearPoint(p1, p2, q1, q2 Point, t, te float64) Point {
+	switch {
+	case math.Abs(t) <= te:
+		return p1
+	case math.Abs(t-1) <= te:
+		return p2
+	}
+	pt := p1.Lerp(p2, t)
+	lr := Distance(p1, p2)
+	if Distance(pt, q1) <= te*lr {
+		return q1
+	}
+	if Distance(pt, q2) <= te*lr {
+		return q2
+	}
+	return pt
+}
+
+// vertexIndex merges points that are closer than eps, using a hash grid
+type vertexIndex struct {
+	eps   float64
+	cells map[[2]int64][]int
+	pts   []Point
+}
+
+func newVertexIndex(eps float64) *vertexIndex {
+	return &vertexIndex{eps: eps, cells: make(map[[2]int64][]int)}
+}
+
+func (v *vertexIndex) cell(p Point) (int64, int64) {
+	return int64(math.Floor(p.X / v.eps)), int64(math.Floor(p.Y / v.eps))
+}
+
+// add returns the index of an existing vertex within eps of p, or adds p
+func (v *vertexIndex) add(p Point) int {
+	cx, cy := v.cell(p)
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, i := range v.cells[[2]int64{cx + dx, cy + dy}] {
+				if Distance(v.pts[i], p) <= v.eps {
+					return i
+				}
+			}
+		}
+	}
+	i := len(v.pts)
+	v.pts = append(v.pts, p)
+	v.cells[[2]int64{cx, cy}] = append(v.cells[[2]int64{cx, cy}], i)
+	return i
+}
+
+// windingIndex answers winding number queries for the edges of a planar graph. Edges
+// are bucketed by their y range (for rays cast towards +x) and by their x range (for
+// rays cast towards +y), so a query only visits edges near the probe point.
+type windingIndex struct {
+	g     *planarGraph
+	byY   edgeBuckets
+	byX   edgeBuckets
+	built bool
+}
+
+type edgeBuckets struct {
+	lo, size float64
+	buckets  [][]int
+}
+
+func newEdgeBuckets(g *planarGraph, coord func(Point) float64) edgeBuckets {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range g.pts {
+		lo = math.Min(lo, coord(p))
+		hi = math.Max(hi, coord(p))
+	}
+	n := int(math.Sqrt(float64(len(g.edges)))) + 1
+	size := (hi - lo) / float64(n)
+	if size <= 0 {
+		size = 1
+	}
+	eb := edgeBuckets{lo: lo, size: size, buckets: make([][]int, n)}
+	for e, edge := range g.edges {
+		a, b := coord(g.pts[edge.a]), coord(g.pts[edge.b])
+		if a > b {
+			a, b = b, a
+		}
+		for k := eb.bucket(a); k <= eb.bucket(b); k++ {
+			eb.buckets[k] = append(eb.buckets[k], e)
+		}
+	}
+	return eb
+}
+
+func (eb edgeBuckets) bucket(v float64) int {
+	k := int((v - eb.lo) / eb.size)
+	if k < 0 {
+		return 0
+	}
+	if k >= len(eb.buckets) {
+		return len(eb.buckets) - 1
+	}
+	return k
+}
+
+func newWindingIndex(g *planarGraph) *windingIndex {
+	return &windingIndex{
+		g:   g,
+		byY: newEdgeBuckets(g, func(p Point) float64 { return p.Y }),
+		byX: newEdgeBuckets(g, func(p Point) float64 { return p.X }),
+	}
+}
+
+// rotateForRay maps a point into the frame used when casting a ray towards +y: the
+// rotation (x, y) -> (y, -x) turns that ray into one towards +x and keeps orientation.
+func rotateForRay(p Point, vertical bool) Point {
+	if vertical {
+		return Point{X: p.Y, Y: -p.X}
+	}
+	return p
+}
+
+// sideWindings returns the winding numbers of the given input set immediately to the
+// left and right of edge e (relative to its canonical direction).
+func (w *windingIndex) sideWindings(e, set int) (left, right int) {
+	g := w.g
+	edge := g.edges[e]
+	pa, pb := g.pts[edge.a], g.pts[edge.b]
+	vertical := math.Abs(pb.X-pa.X) > math.Abs(pb.Y-pa.Y)
+	m := Midpoint(pa, pb)
+	var candidates []int
+	if vertical {
+		candidates = w.byX.buckets[w.byX.bucket(m.X)]
+	} else {
+		candidates = w.byY.buckets[w.byY.bucket(m.Y)]
+	}
+	p := rotateForRay(m, vertical)
+	wOther := 0
+	for _, f := range candidates {
+		if f == e {
+			continue
+		}
+		d := g.edges[f].delta[set]
+		if d == 0 {
+			continue
+		}
+		a := rotateForRay(g.pts[g.edges[f].a], vertical)
+		b := rotateForRay(g.pts[g.edges[f].b], vertical)
+		wOther += d * windingContribution(a, b, p)
+	}
+	d := edge.delta[set]
+	if rotateForRay(pb, vertical).Y > rotateForRay(pa, vertical).Y {
+		return wOther + d, wOther
+	}
+	return wOther, wOther - d
+}
+
+// windingContribution is the signed crossing of a ray from p towards +x with the
+// directed segment a->b, using half-open y intervals so shared vertices count once.
+func windingContribution(a, b, p Point) int {
+	if a.Y <= p.Y {
+		if b.Y > p.Y && orient2(a, b, p) > 0 {
+			return 1
+		}
+	} else if b.Y <= p.Y && orient2(a, b, p) < 0 {
+		return -1
+	}
+	return 0
+}
+
+// traceLoops links directed edges into closed loops. At a vertex with several
+// outgoing edges the sharpest left turn is taken, which keeps the region on the left
+// of each loop and separates loops that only touch at a vertex.
+func traceLoops(pts []Point, edges [][2]int) [][]int {
+	out := make(map[int][]int)
+	for i, e := range edges {
+		out[e[0]] = append(out[e[0]], i)
+	}
+	used := make([]bool, len(edges))
+	var loops [][]int
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		var loop []int
+		cur := start
+		for steps := 0; steps <= len(edges); steps++ {
+			used[cur] = true
+			loop = append(loop, edges[cur][0])
+			v := edges[cur][1]
+			back := math.Atan2(pts[edges[cur][0]].Y-pts[v].Y, pts[edges[cur][0]].X-pts[v].X)
+			next := -1
+			best := math.Inf(1)
+			for _, cand := range out[v] {
+				q := pts[edges[cand][1]]
+				turn := back - math.Atan2(q.Y-pts[v].Y, q.X-pts[v].X)
+				for turn <= 0 {
+					turn += Tau
+				}
+				if turn < best {
+					best, next = turn, cand
+				}
+			}
+			if next == start {
+				loops = append(loops, loop)
+				break
+			}
+			if next < 0 || used[next] {
+				break
+			}
+			cur = next
+		}
+	}
+	return loops
+}
+
+// loopsToCurves converts vertex loops to closed curves, dropping collinear vertices
+// and loops that enclose no area
+func loopsToCurves(pts []Point, loops [][]int) []Curve {
+	var curves []Curve
+	for _, loop := range loops {
+		poly := make([]Point, len(loop))
+		for i, v := range loop {
+			poly[i] = pts[v]
+		}
+		poly = removeCollinearPoints(poly)
+		if len(poly) < 3 || polygonSignedArea(poly) == 0 {
+			continue
+		}
+		curves = append(curves, Curve{Points: poly, Closed: true})
+	}
+	return curves
+}
+
+// removeCollinearPoints drops vertices of a closed polygon that lie on a straight
+// continuation of their neighbours
+func removeCollinearPoints(poly []Point) []Point {
+	changed := true
+	for changed && len(poly) >= 3 {
+		changed = false
+		n := len(poly)
+		out := make([]Point, 0, n)
+		for i := 0; i < n; i++ {
+			a := poly[(i+n-1)%n]
+			b := poly[i]
+			c := poly[(i+1)%n]
+			ab := Vec2FromPoints(a, b)
+			bc := Vec2FromPoints(b, c)
+			cross := ab.X*bc.Y - ab.Y*bc.X
+			if math.Abs(cross) <= 1e-12*ab.Mag()*bc.Mag() && ab.Dot(bc) > 0 {
+				changed = true
+				continue
+			}
+			out = append(out, b)
+		}
+		if changed {
+			poly = out
+		}
+	}
+	return poly
+}
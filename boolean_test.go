@@ -0,0 +1,162 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func square(x, y, s float64) Curve {
+	return Rect{X: x, Y: y, W: s, H: s}.ToCurve()
+}
+
+// signedAreaSum adds the signed areas of curves, so holes (clockwise) subtract
+func signedAreaSum(curves []Curve) float64 {
+	var sum float64
+	for _, c := range curves {
+		sum += c.SignedArea()
+	}
+	return sum
+}
+
+func TestBooleanCurves_overlappingSquares(t *testing.T) {
+	a := square(0, 0, 2)
+	b := square(1, 1, 2)
+	tests := []struct {
+		name  string
+		op    BoolOp
+		area  float64
+		count int
+	}{
+		{"union", BoolUnion, 7, 1},
+		{"intersection", BoolIntersection, 1, 1},
+		{"difference", BoolDifference, 3, 1},
+		{"xor", BoolXor, 6, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BooleanCurves([]Curve{a}, []Curve{b}, tt.op, NonZero)
+			require.Len(t, got, tt.count)
+			assert.InDelta(t, tt.area, signedAreaSum(got), 1e-9)
+			for _, c := range got {
+				assert.True(t, c.Closed)
+				assert.Greater(t, c.SignedArea(), 0.0)
+			}
+		})
+	}
+}
+
+func TestCurveUnion_disjoint(t *testing.T) {
+	got := CurveUnion(square(0, 0, 1), square(3, 0, 1))
+	require.Len(t, got, 2)
+	assert.InDelta(t, 2, signedAreaSum(got), 1e-9)
+}
+
+func TestCurveUnion_touchingCorner(t *testing.T) {
+	got := CurveUnion(square(0, 0, 1), square(1, 1, 1))
+	require.Len(t, got, 2)
+	for _, c := range got {
+		assert.Len(t, c.Points, 4)
+		assert.InDelta(t, 1, c.Area(), 1e-9)
+	}
+}
+
+func TestCurveUnion_sharedEdge(t *testing.T) {
+	got := CurveUnion(square(0, 0, 1), square(1, 0, 1))
+	require.Len(t, got, 1)
+	assert.Len(t, got[0].Points, 4, "collinear vertices on the merged edges should be removed")
+	assert.InDelta(t, 2, got[0].Area(), 1e-9)
+}
+
+func TestCurveDifference_hole(t *testing.T) {
+	got := CurveDifference(square(0, 0, 4), square(1, 1, 2))
+	require.Len(t, got, 2)
+	assert.InDelta(t, 12, signedAreaSum(got), 1e-9)
+	var holes int
+	for _, c := range got {
+		if c.SignedArea() < 0 {
+			holes++
+		}
+	}
+	assert.Equal(t, 1, holes)
+}
+
+func TestCurveIntersection_clockwiseInput(t *testing.T) {
+	a := square(0, 0, 2)
+	a.Reverse()
+	got := CurveIntersection(a, square(1, 1, 2))
+	require.Len(t, got, 1)
+	assert.InDelta(t, 1, signedAreaSum(got), 1e-9)
+}
+
+func TestCurveIntersection_nonConvex(t *testing.T) {
+	// U shape: a 3x3 square with a 1x2 notch cut from the top middle
+	u := Curve{Closed: true, Points: []Point{
+		{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3},
+	}}
+	bar := Rect{X: -1, Y: 2, W: 5, H: 0.5}.ToCurve()
+	got := CurveIntersection(u, bar)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 1, signedAreaSum(got), 1e-9)
+}
+
+func TestBooleanCurves_holesEvenOdd(t *testing.T) {
+	// a ring given as two curves wound the same way
+	ring := []Curve{square(0, 0, 4), square(1, 1, 2)}
+	got := BooleanCurves(ring, []Curve{square(1.5, 1.5, 1)}, BoolUnion, EvenOdd)
+	assert.InDelta(t, 13, signedAreaSum(got), 1e-9)
+	got = BooleanCurves(ring, []Curve{Rect{X: -1, Y: 1.5, W: 6, H: 1}.ToCurve()}, BoolIntersection, EvenOdd)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 2, signedAreaSum(got), 1e-9)
+}
+
+func TestBooleanCurves_selfIntersecting(t *testing.T) {
+	bowtie := Curve{Closed: true, Points: []Point{{0, 0}, {2, 2}, {2, 0}, {0, 2}}}
+	got := BooleanCurves([]Curve{bowtie}, nil, BoolUnion, NonZero)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 2, signedAreaSum(got), 1e-9)
+}
+
+func TestCurveDifference_circleFromVoronoiCell(t *testing.T) {
+	cells, err := VoronoiWithRect(unitSquare(), []Point{{0.25, 0.5}, {0.75, 0.5}})
+	require.NoError(t, err)
+	circle := Circle{Center: Point{X: 0.5, Y: 0.5}, Radius: 0.2}.ToCurve(64)
+	got := CurveDifference(cells[0], circle)
+	require.Len(t, got, 1)
+	want := cells[0].Area() - circle.Area()/2
+	assert.InDelta(t, want, signedAreaSum(got), 1e-9)
+}
+
+func TestBooleanCurves_empty(t *testing.T) {
+	assert.Nil(t, BooleanCurves(nil, nil, BoolUnion, NonZero))
+	assert.Empty(t, CurveIntersection(square(0, 0, 1), square(5, 5, 1)))
+}
+
+func randomStar(rng *rand.Rand, c Point, n int) Curve {
+	var curve Curve
+	curve.Closed = true
+	for i := 0; i < n; i++ {
+		a := Tau * float64(i) / float64(n)
+		r := 0.3 + 0.7*rng.Float64()
+		curve.AddPoint(c.X+r*math.Cos(a), c.Y+r*math.Sin(a))
+	}
+	return curve
+}
+
+func TestBooleanCurves_inclusionExclusion(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		a := randomStar(rng, Point{X: rng.Float64(), Y: rng.Float64()}, 5+rng.Intn(20))
+		b := randomStar(rng, Point{X: rng.Float64(), Y: rng.Float64()}, 5+rng.Intn(20))
+		union := signedAreaSum(CurveUnion(a, b))
+		inter := signedAreaSum(CurveIntersection(a, b))
+		diff := signedAreaSum(CurveDifference(a, b))
+		xor := signedAreaSum(CurveXor(a, b))
+		assert.InDelta(t, a.Area()+b.Area(), union+inter, 1e-9)
+		assert.InDelta(t, a.Area()-inter, diff, 1e-9)
+		assert.InDelta(t, union-inter, xor, 1e-9)
+	}
+}
@@ -0,0 +1,225 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+)
+
+// MinEnclosingCircle returns the smallest circle containing all of the points, using
+// Welzl's algorithm in expected O(n) time. The points are visited in a shuffled but
+// fixed order, so the result is deterministic. An empty input gives the zero Circle.
+func MinEnclosingCircle(points []Point) Circle {
+	if len(points) == 0 {
+		return Circle{}
+	}
+	pts := append([]Point(nil), points...)
+	rng := rand.New(rand.NewSource(1))
+	rng.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+
+	c := Circle{Center: pts[0]}
+	for i := 1; i < len(pts); i++ {
+		if enclosesPoint(c, pts[i]) {
+			continue
+		}
+		// pts[i] is on the boundary of the circle for pts[:i+1]
+		c = Circle{Center: pts[i]}
+		for j := 0; j < i; j++ {
+			if enclosesPoint(c, pts[j]) {
+				continue
+			}
+			// pts[i] and pts[j] are both on the boundary
+			c = diametralCircle(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if !enclosesPoint(c, pts[k]) {
+					c = circleThrough(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+	return c
+}
+
+// enclosesPoint is a containment test for circles that tolerates rounding
+func enclosesPoint(c Circle, p Point) bool {
+	return Distance(c.Center, p) <= c.Radius*(1+1e-12)+1e-300
+}
+
+func diametralCircle(p, q Point) Circle {
+	return Circle{Center: Midpoint(p, q), Radius: Distance(p, q) / 2}
+}
+
+// circleThrough returns the circle through three points, or the diametral circle of
+// the farthest pair when they are collinear
+func circleThrough(a, b, c Point) Circle {
+	bx, by := b.X-a.X, b.Y-a.Y
+	cx, cy := c.X-a.X, c.Y-a.Y
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		best := diametralCircle(a, b)
+		for _, cand := range []Circle{diametralCircle(a, c), diametralCircle(b, c)} {
+			if cand.Radius > best.Radius {
+				best = cand
+			}
+		}
+		return best
+	}
+	b2 := bx*bx + by*by
+	c2 := cx*cx + cy*cy
+	ux := (cy*b2 - by*c2) / d
+	uy := (bx*c2 - cx*b2) / d
+	center := Point{X: a.X + ux, Y: a.Y + uy}
+	r := math.Max(Distance(center, a), math.Max(Distance(center, b), Distance(center, c)))
+	return Circle{Center: center, Radius: r}
+}
+
+// MinAreaBoundingBox returns the rotated rectangle of smallest area containing all of
+// the points, as a closed counterclockwise curve, together with the angle of its first
+// edge in radians, in [0, Pi). One side of the optimal box always lies along an edge
+// of the convex hull, which rotating calipers visit in O(n) after the O(n log n) hull.
+func MinAreaBoundingBox(points []Point) (Curve, float64) {
+	box, _ := calipersBoxes(points)
+	return box.curve(), box.angle
+}
+
+// MinWidthBoundingBox returns the rotated rectangle containing all of the points whose
+// shorter side is as short as possible (the width of the point set), as a closed
+// counterclockwise curve, together with the angle of its first edge, which runs along
+// the longer side, in radians in [0, Pi).
+func MinWidthBoundingBox(points []Point) (Curve, float64) {
+	_, box := calipersBoxes(points)
+	return box.curve(), box.angle
+}
+
+// Diameter returns the segment between the two points farthest apart, found with
+// rotating calipers over the convex hull
+func Diameter(points []Point) Line {
+	hull := ConvexHull(points).Points
+	n := len(hull)
+	switch n {
+	case 0:
+		return Line{}
+	case 1:
+		return Line{P: hull[0], Q: hull[0]}
+	}
+	best := Line{P: hull[0], Q: hull[1]}
+	bestD := SquaredDistance(hull[0], hull[1])
+	j := 1
+	for i := 0; i < n; i++ {
+		a, b := hull[i], hull[(i+1)%n]
+		// advance to the vertex farthest from the line through edge i
+		for orient2(a, b, hull[(j+1)%n]) > orient2(a, b, hull[j]) {
+			j = (j + 1) % n
+		}
+		for _, p := range []Point{a, b} {
+			if d := SquaredDistance(p, hull[j]); d > bestD {
+				best, bestD = Line{P: p, Q: hull[j]}, d
+			}
+		}
+	}
+	return best
+}
+
+// Width returns the minimum distance between two parallel lines enclosing all of the
+// points
+func Width(points []Point) float64 {
+	_, box := calipersBoxes(points)
+	return box.height
+}
+
+// orientedBox is a rectangle with corner origin, sides along the unit vectors u and v
+// (v is u rotated a quarter turn counterclockwise) and the given lengths along each
+type orientedBox struct {
+	origin        Point
+	u             Vec2
+	width, height float64
+	angle         float64
+}
+
+func (b orientedBox) curve() Curve {
+	v := Vec2{X: -b.u.Y, Y: b.u.X}
+	along := b.u.Scale(b.width)
+	up := v.Scale(b.height)
+	o := b.origin.ToVec2()
+	return Curve{Closed: true, Points: []Point{
+		o.ToPoint(),
+		o.Add(along).ToPoint(),
+		o.Add(along).Add(up).ToPoint(),
+		o.Add(up).ToPoint(),
+	}}
+}
+
+// calipersBoxes finds the minimum area and minimum width boxes of a point set. For
+// each hull edge the calipers track the extreme vertices along the edge direction
+// and its normal; each pointer only moves forward, so the loop is linear.
+func calipersBoxes(points []Point) (minArea, minWidth orientedBox) {
+	hull := ConvexHull(points).Points
+	n := len(hull)
+	switch n {
+	case 0:
+		return
+	case 1:
+		b := orientedBox{origin: hull[0], u: Vec2{X: 1}}
+		return b, b
+	case 2:
+		d := Vec2FromPoints(hull[0], hull[1])
+		b := orientedBox{origin: hull[0], u: d.Normalize(), width: d.Mag()}
+		b.angle = normalizeHalfTurn(math.Atan2(d.Y, d.X))
+		return b, b
+	}
+	proj := func(i int, dir Vec2) float64 {
+		return Vec2FromPoint(hull[i%n]).Dot(dir)
+	}
+	minArea.width, minArea.height = math.Inf(1), math.Inf(1)
+	minWidth.width, minWidth.height = math.Inf(1), math.Inf(1)
+	right, top, left := 1, 1, 1 // unbounded indices, taken modulo n
+	for i := 0; i < n; i++ {
+		u := Vec2FromPoints(hull[i], hull[(i+1)%n]).Normalize()
+		v := Vec2{X: -u.Y, Y: u.X}
+		for proj(right+1, u) > proj(right, u) {
+			right++
+		}
+		if top < right {
+			top = right
+		}
+		for proj(top+1, v) > proj(top, v) {
+			top++
+		}
+		if left < top {
+			left = top
+		}
+		for proj(left+1, u) < proj(left, u) {
+			left++
+		}
+		base := Vec2FromPoint(hull[i])
+		minU := proj(left, u)
+		width := proj(right, u) - minU
+		height := proj(top, v) - base.Dot(v)
+		origin := u.Scale(minU).Add(v.Scale(base.Dot(v))).ToPoint()
+		box := orientedBox{
+			origin: origin,
+			u:      u,
+			width:  width,
+			height: height,
+			angle:  normalizeHalfTurn(math.Atan2(u.Y, u.X)),
+		}
+		if width*height < minArea.width*minArea.height {
+			minArea = box
+		}
+		if height < minWidth.height {
+			minWidth = box
+		}
+	}
+	return minArea, minWidth
+}
+
+// normalizeHalfTurn maps an angle to [0, Pi)
+func normalizeHalfTurn(a float64) float64 {
+	a = math.Mod(a, Pi)
+	if a < 0 {
+		a += Pi
+	}
+	if a >= Pi {
+		a = 0
+	}
+	return a
+}
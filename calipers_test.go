@@ -0,0 +1,116 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinEnclosingCircle(t *testing.T) {
+	tri := []Point{{0, 0}, {2, 0}, {1, 1.5}, {1, 0.5}}
+	c := MinEnclosingCircle(tri)
+	want := circleThrough(tri[0], tri[1], tri[2])
+	assert.InDelta(t, want.Radius, c.Radius, 1e-12)
+	assert.InDelta(t, want.Center.X, c.Center.X, 1e-12)
+	assert.InDelta(t, want.Center.Y, c.Center.Y, 1e-12)
+
+	// an obtuse triangle is enclosed by the circle on its longest side
+	c = MinEnclosingCircle([]Point{{0, 0}, {4, 0}, {2, 0.5}})
+	assert.InDelta(t, 2, c.Radius, 1e-12)
+	assert.InDelta(t, 2, c.Center.X, 1e-12)
+
+	assert.Equal(t, Circle{Center: Point{3, 4}}, MinEnclosingCircle([]Point{{3, 4}}))
+	assert.Equal(t, Circle{}, MinEnclosingCircle(nil))
+}
+
+func TestMinEnclosingCircle_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for trial := 0; trial < 20; trial++ {
+		pts := make([]Point, 5+rng.Intn(300))
+		for i := range pts {
+			pts[i] = Point{X: rng.NormFloat64(), Y: 3 * rng.NormFloat64()}
+		}
+		c := MinEnclosingCircle(pts)
+		onBoundary := 0
+		for _, p := range pts {
+			d := Distance(c.Center, p)
+			assert.LessOrEqual(t, d, c.Radius*(1+1e-9))
+			if d >= c.Radius*(1-1e-9) {
+				onBoundary++
+			}
+		}
+		assert.GreaterOrEqual(t, onBoundary, 2)
+	}
+}
+
+func rotated(p Point, a float64) Point {
+	s, c := math.Sincos(a)
+	return Point{X: p.X*c - p.Y*s, Y: p.X*s + p.Y*c}
+}
+
+func TestMinAreaBoundingBox(t *testing.T) {
+	// a 4x1 rectangle rotated by 30 degrees
+	rect := Rect{X: -2, Y: -0.5, W: 4, H: 1}.ToCurve()
+	for i, p := range rect.Points {
+		rect.Points[i] = rotated(p, Pi/6)
+	}
+	box, angle := MinAreaBoundingBox(rect.Points)
+	require.Len(t, box.Points, 4)
+	assert.True(t, box.IsCCW())
+	assert.InDelta(t, 4, box.Area(), 1e-9)
+	assert.InDelta(t, 0, math.Sin(2*(angle-Pi/6)), 1e-9, "box edges follow the rectangle")
+
+	box, angle = MinWidthBoundingBox(rect.Points)
+	assert.InDelta(t, 4, box.Area(), 1e-9)
+	assert.InDelta(t, Pi/6, angle, 1e-9)
+	assert.InDelta(t, 1, Width(rect.Points), 1e-9)
+}
+
+func TestMinAreaBoundingBox_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for trial := 0; trial < 20; trial++ {
+		pts := make([]Point, 3+rng.Intn(100))
+		for i := range pts {
+			pts[i] = rotated(Point{X: rng.Float64(), Y: 0.3 * rng.Float64()}, rng.Float64()*Tau)
+		}
+		box, _ := MinAreaBoundingBox(pts)
+		hull := ConvexHull(pts)
+		// brute force over a fine sweep of angles never beats the calipers
+		for a := 0.0; a < Pi/2; a += 0.01 {
+			u := Vec2{X: math.Cos(a), Y: math.Sin(a)}
+			v := Vec2{X: -u.Y, Y: u.X}
+			minU, maxU, minV, maxV := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+			for _, p := range hull.Points {
+				pu, pv := p.ToVec2().Dot(u), p.ToVec2().Dot(v)
+				minU, maxU = math.Min(minU, pu), math.Max(maxU, pu)
+				minV, maxV = math.Min(minV, pv), math.Max(maxV, pv)
+			}
+			assert.LessOrEqual(t, box.Area(), (maxU-minU)*(maxV-minV)+1e-12)
+			assert.LessOrEqual(t, Width(pts), math.Min(maxU-minU, maxV-minV)+1e-12)
+		}
+		for _, p := range pts {
+			assert.True(t, box.ContainsPoint(p, NonZero))
+		}
+	}
+}
+
+func TestDiameter(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for trial := 0; trial < 20; trial++ {
+		pts := make([]Point, 2+rng.Intn(100))
+		for i := range pts {
+			pts[i] = Point{X: rng.NormFloat64(), Y: rng.NormFloat64()}
+		}
+		var want float64
+		for i := range pts {
+			for j := i + 1; j < len(pts); j++ {
+				want = math.Max(want, Distance(pts[i], pts[j]))
+			}
+		}
+		assert.InDelta(t, want, Diameter(pts).Length(), 1e-12)
+	}
+	assert.Equal(t, Line{}, Diameter(nil))
+}
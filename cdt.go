@@ -0,0 +1,566 @@
+package gaul
+
+import "math"
+
+// ConstrainedDelaunayTriangles returns a constrained Delaunay triangulation of the
+// sites: every constraint segment and every edge of the boundary curves appears as a
+// union of triangle edges, and apart from that the triangles are as close to Delaunay
+// as the constraints allow. Constraints that cross each other, or pass through sites,
+// are split where they meet. Each output [Triangle] is oriented counterclockwise.
+//
+// Without boundary curves the triangulation covers the convex hull of the sites and
+// of the constraint end points. With boundary curves (outer boundaries and holes,
+// interpreted with the given fill rule) only the triangles inside the region are kept;
+// sites and constraints outside it still shape the triangulation but are dropped
+// with their triangles.
+func ConstrainedDelaunayTriangles(sites []Point, constraints []Line, boundary []Curve, rule FillRule) []Triangle {
+	tr, keep := constrainedTriangulation(sites, constraints, boundary, rule)
+	if tr == nil {
+		return nil
+	}
+	return tr.triangles(keep)
+}
+
+// constrainedTriangulation builds the triangulation behind [ConstrainedDelaunayTriangles]
+// and returns it with the test for triangles inside the domain (nil when every
+// triangle off the super-triangle is). It returns nil if there are fewer than three
+// points.
+func constrainedTriangulation(sites []Point, constraints []Line, boundary []Curve, rule FillRule) (*triangulation, func(Triangle) bool) {
+	all := append([]Point(nil), sites...)
+	for _, l := range constraints {
+		all = append(all, l.P, l.Q)
+	}
+	for _, c := range boundary {
+		all = append(all, c.Points...)
+	}
+	if len(all) < 3 {
+		return nil, nil
+	}
+	tr := newTriangulation(all)
+	for _, p := range sites {
+		tr.insertPoint(p)
+	}
+	for _, l := range constraints {
+		tr.insertSegment(l.P, l.Q)
+	}
+	for _, c := range boundary {
+		pts := dedupeConsecutivePoints(c.Points)
+		for i := range pts {
+			tr.insertSegment(pts[i], pts[(i+1)%len(pts)])
+		}
+	}
+	if len(boundary) == 0 {
+		hull := ConvexHull(all).Points
+		for i := range hull {
+			tr.insertSegment(hull[i], hull[(i+1)%len(hull)])
+		}
+		return tr, nil
+	}
+	cl := NewClipper(boundary, rule)
+	return tr, func(t Triangle) bool {
+		return cl.contains(t.Centroid())
+	}
+}
+
+// triangulation is an incrementally built triangulation with adjacency, used for
+// constrained Delaunay triangulation and refinement. It starts from a super-triangle
+// (vertices 0, 1 and 2) enclosing all points that will be inserted. Triangles are
+// counterclockwise; edge k of triangle t runs from tris[t][k] to tris[t][(k+1)%3],
+// adj[t][k] is the triangle on the other side (-1 if none) and fixed[t][k] marks
+// constrained edges, which are never flipped. Removing a vertex leaves dead triangles
+// and vertices, with corners -1, whose slots are reused. With ghosts set the
+// super-triangle corners lie at infinity, see [newGhostTriangulation].
+type triangulation struct {
+	pts       []Point
+	tris      [][3]int
+	adj       [][3]int
+	fixed     [][3]bool
+	vertTri   []int // a triangle incident to each vertex
+	last      int   // where the next point location starts
+	state     uint64
+	free      []int        // dead triangles
+	freeVerts []int        // removed vertices
+	touched   map[int]bool // if not nil, collects the corners of changed triangles
+	ghosts    bool
+}
+
+func newTriangulation(bounds []Point) *triangulation {
+	s0, s1, s2 := superTriangle(bounds)
+	pts := []Point{s0, s1, s2}
+	a, b, c := ccwOrder(0, 1, 2, pts)
+	tr := &triangulation{pts: pts, vertTri: make([]int, 3), state: 88172645463325252}
+	t := tr.newTri()
+	tr.setTri(t, a, b, c)
+	tr.adj[t] = [3]int{-1, -1, -1}
+	return tr
+}
+
+func (tr *triangulation) newTri() int {
+	if n := len(tr.free); n > 0 {
+		t := tr.free[n-1]
+		tr.free = tr.free[:n-1]
+		tr.adj[t], tr.fixed[t] = [3]int{-1, -1, -1}, [3]bool{}
+		return t
+	}
+	tr.tris = append(tr.tris, [3]int{})
+	tr.adj = append(tr.adj, [3]int{-1, -1, -1})
+	tr.fixed = append(tr.fixed, [3]bool{})
+	return len(tr.tris) - 1
+}
+
+func (tr *triangulation) setTri(t, a, b, c int) {
+	tr.tris[t] = [3]int{a, b, c}
+	tr.vertTri[a], tr.vertTri[b], tr.vertTri[c] = t, t, t
+	if tr.touched != nil {
+		tr.touched[a], tr.touched[b], tr.touched[c] = true, true, true
+	}
+}
+
+// link makes u the neighbour of t across edge k, on both sides
+func (tr *triangulation) link(t, k, u int, fixed bool) {
+	tr.adj[t][k], tr.fixed[t][k] = u, fixed
+	if u < 0 {
+		return
+	}
+	if j := tr.edgeIndex(u, tr.tris[t][(k+1)%3], tr.tris[t][k]); j >= 0 {
+		tr.adj[u][j], tr.fixed[u][j] = t, fixed
+	}
+}
+
+// edgeIndex returns the index of edge a->b in triangle t, or -1
+func (tr *triangulation) edgeIndex(t, a, b int) int {
+	for k := 0; k < 3; k++ {
+		if tr.tris[t][k] == a && tr.tris[t][(k+1)%3] == b {
+			return k
+		}
+	}
+	return -1
+}
+
+func (tr *triangulation) vertexIndex(t, v int) int {
+	for k := 0; k < 3; k++ {
+		if tr.tris[t][k] == v {
+			return k
+		}
+	}
+	return -1
+}
+
+func (tr *triangulation) random() uint64 {
+	tr.state ^= tr.state << 13
+	tr.state ^= tr.state >> 7
+	tr.state ^= tr.state << 17
+	return tr.state
+}
+
+// point location results
+const (
+	locInside = iota
+	locEdge
+	locVertex
+	locOutside
+)
+
+// locate finds the triangle containing p with a stochastic visibility walk, and
+// whether p is inside it, on its edge k or at its vertex k
+func (tr *triangulation) locate(p Point) (t, kind, k int) {
+	t = tr.last
+	if t >= len(tr.tris) {
+		t = 0
+	}
+	for steps := 0; steps < len(tr.tris)+16; steps++ {
+		next := -1
+		r := int(tr.random() % 3)
+		for j := 0; j < 3 && next < 0; j++ {
+			e := (r + j) % 3
+			if tr.orientTo(tr.tris[t][e], tr.tris[t][(e+1)%3], p) < 0 {
+				next = tr.adj[t][e]
+				if next < 0 {
+					return t, locOutside, e
+				}
+			}
+		}
+		if next < 0 {
+			kind, k = tr.classify(t, p)
+			return t, kind, k
+		}
+		t = next
+	}
+	// the walk can cycle around constrained edges; fall back to a scan
+	for t := range tr.tris {
+		if tr.tris[t][0] < 0 {
+			continue
+		}
+		if kind, k := tr.classify(t, p); kind != locOutside {
+			return t, kind, k
+		}
+	}
+	return 0, locOutside, 0
+}
+
+func (tr *triangulation) classify(t int, p Point) (kind, k int) {
+	kind, k = locInside, -1
+	for e := 0; e < 3; e++ {
+		if v := tr.tris[t][e]; (!tr.ghosts || v >= 3) && tr.pts[v].IsEqual(p) {
+			return locVertex, e
+		}
+	}
+	for e := 0; e < 3; e++ {
+		o := tr.orientTo(tr.tris[t][e], tr.tris[t][(e+1)%3], p)
+		if o < 0 {
+			return locOutside, e
+		}
+		if o == 0 {
+			kind, k = locEdge, e
+		}
+	}
+	return kind, k
+}
+
+// insertPoint adds p and restores the (constrained) Delaunay property around it. It
+// returns the index of the vertex at p, which already exists if p is a duplicate.
+func (tr *triangulation) insertPoint(p Point) int {
+	t, kind, k := tr.locate(p)
+	return tr.insertAt(p, t, kind, k)
+}
+
+// insertAt inserts p where [triangulation.locate] found it
+func (tr *triangulation) insertAt(p Point, t, kind, k int) int {
+	switch kind {
+	case locVertex:
+		return tr.tris[t][k]
+	case locOutside:
+		return -1
+	}
+	var v int
+	if n := len(tr.freeVerts); n > 0 {
+		v = tr.freeVerts[n-1]
+		tr.freeVerts = tr.freeVerts[:n-1]
+		tr.pts[v], tr.vertTri[v] = p, t
+	} else {
+		v = len(tr.pts)
+		tr.pts = append(tr.pts, p)
+		tr.vertTri = append(tr.vertTri, t)
+	}
+	if kind == locEdge {
+		tr.splitEdge(t, k, v)
+	} else {
+		tr.splitTriangle(t, v)
+	}
+	return v
+}
+
+// splitTriangle connects vertex v, inside triangle t, to its corners
+func (tr *triangulation) splitTriangle(t, v int) {
+	a, b, c := tr.tris[t][0], tr.tris[t][1], tr.tris[t][2]
+	nAB, nBC, nCA := tr.adj[t][0], tr.adj[t][1], tr.adj[t][2]
+	fAB, fBC, fCA := tr.fixed[t][0], tr.fixed[t][1], tr.fixed[t][2]
+	t2, t3 := tr.newTri(), tr.newTri()
+	tr.setTri(t, a, b, v)
+	tr.setTri(t2, b, c, v)
+	tr.setTri(t3, c, a, v)
+	tr.link(t, 0, nAB, fAB)
+	tr.link(t2, 0, nBC, fBC)
+	tr.link(t3, 0, nCA, fCA)
+	tr.link(t, 1, t2, false)
+	tr.link(t2, 1, t3, false)
+	tr.link(t3, 1, t, false)
+	tr.last = t
+	tr.legalize([][2]int{{t, 0}, {t2, 0}, {t3, 0}})
+}
+
+// splitEdge inserts vertex v on edge k of triangle t, splitting the triangles on
+// both sides. A constrained edge stays constrained in both halves.
+func (tr *triangulation) splitEdge(t, k, v int) {
+	a, b, c := tr.tris[t][k], tr.tris[t][(k+1)%3], tr.tris[t][(k+2)%3]
+	u := tr.adj[t][k]
+	f := tr.fixed[t][k]
+	nBC, fBC := tr.adj[t][(k+1)%3], tr.fixed[t][(k+1)%3]
+	nCA, fCA := tr.adj[t][(k+2)%3], tr.fixed[t][(k+2)%3]
+	t2 := tr.newTri()
+	tr.setTri(t, a, v, c)
+	tr.setTri(t2, v, b, c)
+	tr.link(t, 2, nCA, fCA)
+	tr.link(t2, 1, nBC, fBC)
+	tr.link(t, 1, t2, false)
+	stack := [][2]int{{t, 2}, {t2, 1}}
+	if u >= 0 {
+		m := tr.edgeIndex(u, b, a)
+		d := tr.tris[u][(m+2)%3]
+		nAD, fAD := tr.adj[u][(m+1)%3], tr.fixed[u][(m+1)%3]
+		nDB, fDB := tr.adj[u][(m+2)%3], tr.fixed[u][(m+2)%3]
+		u2 := tr.newTri()
+		tr.setTri(u, b, v, d)
+		tr.setTri(u2, v, a, d)
+		tr.link(u, 2, nDB, fDB)
+		tr.link(u2, 1, nAD, fAD)
+		tr.link(u, 1, u2, false)
+		tr.link(t, 0, u2, f)
+		tr.link(t2, 0, u, f)
+		stack = append(stack, [2]int{u, 2}, [2]int{u2, 1})
+	} else {
+		tr.link(t, 0, -1, f)
+		tr.link(t2, 0, -1, f)
+	}
+	tr.last = t
+	tr.legalize(stack)
+}
+
+// legalize flips edges that fail the empty circumcircle test, starting from the given
+// edges (t, k), each opposite the newly inserted vertex of t
+func (tr *triangulation) legalize(stack [][2]int) {
+	for len(stack) > 0 {
+		t, k := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		if tr.fixed[t][k] || tr.adj[t][k] < 0 || tr.isLocallyDelaunay(t, k) {
+			continue
+		}
+		tr.flip(t, k)
+		// after the flip the new vertex is the last corner of both triangles
+		stack = append(stack, [2]int{t, 0}, [2]int{tr.adj[t][1], 0})
+	}
+}
+
+// isLocallyDelaunay reports whether the vertex across edge k of t lies outside the
+// circumcircle of t
+func (tr *triangulation) isLocallyDelaunay(t, k int) bool {
+	u := tr.adj[t][k]
+	a, b, c := tr.tris[t][k], tr.tris[t][(k+1)%3], tr.tris[t][(k+2)%3]
+	d := tr.tris[u][(tr.edgeIndex(u, b, a)+2)%3]
+	return !tr.inCircle(a, b, c, d)
+}
+
+// flip replaces edge k of t, a->b with c opposite in t and d opposite in the
+// neighbour u, by the edge d->c. Afterwards t is (a, d, c) and u is (d, b, c).
+func (tr *triangulation) flip(t, k int) {
+	u := tr.adj[t][k]
+	a, b, c := tr.tris[t][k], tr.tris[t][(k+1)%3], tr.tris[t][(k+2)%3]
+	m := tr.edgeIndex(u, b, a)
+	d := tr.tris[u][(m+2)%3]
+	nAD, fAD := tr.adj[u][(m+1)%3], tr.fixed[u][(m+1)%3]
+	nDB, fDB := tr.adj[u][(m+2)%3], tr.fixed[u][(m+2)%3]
+	nBC, fBC := tr.adj[t][(k+1)%3], tr.fixed[t][(k+1)%3]
+	nCA, fCA := tr.adj[t][(k+2)%3], tr.fixed[t][(k+2)%3]
+	tr.setTri(t, a, d, c)
+	tr.setTri(u, d, b, c)
+	tr.link(t, 0, nAD, fAD)
+	tr.link(t, 2, nCA, fCA)
+	tr.link(u, 0, nDB, fDB)
+	tr.link(u, 1, nBC, fBC)
+	tr.link(t, 1, u, false)
+}
+
+// around returns the triangles incident to vertex v
+func (tr *triangulation) around(v int) []int {
+	start := tr.vertTri[v]
+	out := []int{start}
+	for t := start; ; {
+		n := tr.adj[t][(tr.vertexIndex(t, v)+2)%3]
+		if n == start {
+			return out
+		}
+		if n < 0 {
+			break
+		}
+		out = append(out, n)
+		t = n
+	}
+	// v is on the outer boundary: collect the other side too
+	for t := start; ; {
+		n := tr.adj[t][tr.vertexIndex(t, v)]
+		if n < 0 {
+			return out
+		}
+		out = append(out, n)
+		t = n
+	}
+}
+
+// findEdge returns a triangle and edge index holding the edge between vertices a and
+// b, in either direction
+func (tr *triangulation) findEdge(a, b int) (int, int, bool) {
+	for _, t := range tr.around(a) {
+		i := tr.vertexIndex(t, a)
+		if tr.tris[t][(i+1)%3] == b {
+			return t, i, true
+		}
+		if tr.tris[t][(i+2)%3] == b {
+			return t, (i + 2) % 3, true
+		}
+	}
+	return -1, -1, false
+}
+
+func (tr *triangulation) setFixed(t, k int) {
+	tr.fixed[t][k] = true
+	if u := tr.adj[t][k]; u >= 0 {
+		tr.fixed[u][tr.edgeIndex(u, tr.tris[t][(k+1)%3], tr.tris[t][k])] = true
+	}
+}
+
+// insertSegment inserts both end points and the segment between them as a constraint
+func (tr *triangulation) insertSegment(p, q Point) {
+	a, b := tr.insertPoint(p), tr.insertPoint(q)
+	if a >= 0 && b >= 0 {
+		tr.insertConstraint(a, b)
+	}
+}
+
+// insertConstraint makes the segment between vertices a and b an edge of the
+// triangulation and marks it constrained. The triangles it crosses are rearranged by
+// flipping (Sloan's algorithm). It is split at vertices lying on it and at
+// constrained edges crossing it.
+func (tr *triangulation) insertConstraint(a, b int) {
+	if a == b {
+		return
+	}
+	if t, k, ok := tr.findEdge(a, b); ok {
+		tr.setFixed(t, k)
+		return
+	}
+	pa, pb := tr.pts[a], tr.pts[b]
+	ab := Vec2FromPoints(pa, pb)
+	// vertices within rounding of the segment count as on it
+	onSegment := func(v int) bool {
+		av := Vec2FromPoints(pa, tr.pts[v])
+		return math.Abs(orient2(pa, pb, tr.pts[v])) <= 1e-12*ab.Mag()*av.Mag() && av.Dot(ab) > 0
+	}
+	// find the edge opposite a that the segment leaves through
+	t, e := -1, -1
+	for _, s := range tr.around(a) {
+		i := tr.vertexIndex(s, a)
+		v1, v2 := tr.tris[s][(i+1)%3], tr.tris[s][(i+2)%3]
+		for _, v := range []int{v1, v2} {
+			if onSegment(v) {
+				tr.insertConstraint(a, v)
+				tr.insertConstraint(v, b)
+				return
+			}
+		}
+		if orient2(pa, pb, tr.pts[v1]) < 0 && orient2(pa, pb, tr.pts[v2]) > 0 {
+			t, e = s, (i+1)%3
+			break
+		}
+	}
+	if t < 0 {
+		return
+	}
+	// walk along the segment collecting the crossed edges as (right, left) pairs
+	var crossed [][2]int
+	for {
+		r, l := tr.tris[t][e], tr.tris[t][(e+1)%3]
+		if tr.fixed[t][e] {
+			// split the constraint where it meets another
+			x := lineIntersectionPoint(pa, pb, tr.pts[r], tr.pts[l])
+			v := len(tr.pts)
+			tr.pts = append(tr.pts, x)
+			tr.vertTri = append(tr.vertTri, t)
+			tr.splitEdge(t, e, v)
+			tr.insertConstraint(a, v)
+			tr.insertConstraint(v, b)
+			return
+		}
+		crossed = append(crossed, [2]int{r, l})
+		u := tr.adj[t][e]
+		m := tr.edgeIndex(u, l, r)
+		w := tr.tris[u][(m+2)%3]
+		if w == b {
+			break
+		}
+		if onSegment(w) {
+			tr.insertConstraint(a, w)
+			tr.insertConstraint(w, b)
+			return
+		}
+		if orient2(pa, pb, tr.pts[w]) > 0 {
+			t, e = u, (m+1)%3
+		} else {
+			t, e = u, (m+2)%3
+		}
+	}
+
+	crosses := func(c, d int) bool {
+		if c == a || c == b || d == a || d == b {
+			return false
+		}
+		pc, pd := tr.pts[c], tr.pts[d]
+		return orient2(pa, pb, pc)*orient2(pa, pb, pd) < 0 && orient2(pc, pd, pa)*orient2(pc, pd, pb) < 0
+	}
+	var created [][2]int
+	for guard := 0; len(crossed) > 0 && guard < 10*len(crossed)*len(crossed)+100; guard++ {
+		edge := crossed[0]
+		crossed = crossed[1:]
+		t, k, ok := tr.findEdge(edge[0], edge[1])
+		if !ok {
+			continue
+		}
+		p, q, c := tr.tris[t][k], tr.tris[t][(k+1)%3], tr.tris[t][(k+2)%3]
+		u := tr.adj[t][k]
+		d := tr.tris[u][(tr.edgeIndex(u, q, p)+2)%3]
+		pc, pd := tr.pts[c], tr.pts[d]
+		if orient2(pc, pd, tr.pts[p])*orient2(pc, pd, tr.pts[q]) >= 0 {
+			// the quadrilateral isn't convex: come back to it later
+			crossed = append(crossed, edge)
+			continue
+		}
+		tr.flip(t, k)
+		if crosses(c, d) {
+			crossed = append(crossed, [2]int{c, d})
+		} else {
+			created = append(created, [2]int{c, d})
+		}
+	}
+	// restore the Delaunay property among the new edges
+	for changed := true; changed; {
+		changed = false
+		for i, edge := range created {
+			if (edge[0] == a && edge[1] == b) || (edge[0] == b && edge[1] == a) {
+				continue
+			}
+			t, k, ok := tr.findEdge(edge[0], edge[1])
+			if !ok || tr.fixed[t][k] || tr.adj[t][k] < 0 || tr.isLocallyDelaunay(t, k) {
+				continue
+			}
+			c := tr.tris[t][(k+2)%3]
+			u := tr.adj[t][k]
+			d := tr.tris[u][(tr.edgeIndex(u, tr.tris[t][(k+1)%3], tr.tris[t][k])+2)%3]
+			tr.flip(t, k)
+			created[i] = [2]int{c, d}
+			changed = true
+		}
+	}
+	if t, k, ok := tr.findEdge(a, b); ok {
+		tr.setFixed(t, k)
+	}
+}
+
+// lineIntersectionPoint returns where the line through p1 and p2 meets the line
+// through q1 and q2, which must not be parallel
+func lineIntersectionPoint(p1, p2, q1, q2 Point) Point {
+	d := Vec2FromPoints(p1, p2)
+	e := Vec2FromPoints(q1, q2)
+	w := Vec2FromPoints(p1, q1)
+	s := (w.X*e.Y - w.Y*e.X) / (d.X*e.Y - d.Y*e.X)
+	return Point{X: p1.X + s*d.X, Y: p1.Y + s*d.Y}
+}
+
+func (tr *triangulation) triangle(t int) Triangle {
+	v := tr.tris[t]
+	return Triangle{A: tr.pts[v[0]], B: tr.pts[v[1]], C: tr.pts[v[2]]}
+}
+
+// triangles returns the triangles not touching the super-triangle for which keep
+// returns true (all of them if keep is nil)
+func (tr *triangulation) triangles(keep func(Triangle) bool) []Triangle {
+	var out []Triangle
+	for _, v := range tr.tris {
+		if v[0] < 3 || v[1] < 3 || v[2] < 3 {
+			continue
+		}
+		t := Triangle{A: tr.pts[v[0]], B: tr.pts[v[1]], C: tr.pts[v[2]]}
+		if keep == nil || keep(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triangleEdgeSet returns the edges of the triangles with their end points ordered
+func triangleEdgeSet(tris []Triangle) map[[2]Point]bool {
+	edges := make(map[[2]Point]bool)
+	add := func(p, q Point) {
+		if q.X < p.X || (q.X == p.X && q.Y < p.Y) {
+			p, q = q, p
+		}
+		edges[[2]Point{p, q}] = true
+	}
+	for _, t := range tris {
+		add(t.A, t.B)
+		add(t.B, t.C)
+		add(t.C, t.A)
+	}
+	return edges
+}
+
+// assertCoversSegment checks that a segment is a union of triangle edges
+func assertCoversSegment(t *testing.T, tris []Triangle, l Line) {
+	t.Helper()
+	covered := 0.0
+	for e := range triangleEdgeSet(tris) {
+		if l.SDF(e[0]) < 1e-9 && l.SDF(e[1]) < 1e-9 {
+			covered += Distance(e[0], e[1])
+		}
+	}
+	assert.InDelta(t, l.Length(), covered, 1e-6, "segment %v", l)
+}
+
+func trianglesArea(tris []Triangle) float64 {
+	var sum float64
+	for _, t := range tris {
+		sum += orient2(t.A, t.B, t.C) / 2
+	}
+	return sum
+}
+
+func TestConstrainedDelaunayTriangles_unconstrained(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	var pts []Point
+	for i := 0; i < 300; i++ {
+		pts = append(pts, Point{X: rng.Float64() * 100, Y: rng.Float64() * 100})
+	}
+	tris := ConstrainedDelaunayTriangles(pts, nil, nil, NonZero)
+	hull := ConvexHull(pts)
+	assert.InDelta(t, hull.Area(), trianglesArea(tris), 1e-6)
+	// with no constraints the result is Delaunay
+	for _, tri := range tris {
+		assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+		for _, p := range pts {
+			assert.False(t, inCircumcircle(tri.A, tri.B, tri.C, p))
+		}
+	}
+	// a triangulation of n points with h of them on the hull has 2n-2-h triangles
+	assert.Len(t, tris, 2*len(pts)-2-len(hull.Points))
+}
+
+func TestConstrainedDelaunayTriangles_constraints(t *testing.T) {
+	// a grid of points with long diagonal constraints that cross each other
+	var pts []Point
+	for i := 0; i <= 10; i++ {
+		for j := 0; j <= 10; j++ {
+			pts = append(pts, Point{X: float64(i) + 0.01*float64(j), Y: float64(j)})
+		}
+	}
+	cons := []Line{
+		{P: Point{X: 0.5, Y: 0.5}, Q: Point{X: 9.5, Y: 8.5}},
+		{P: Point{X: 0.5, Y: 8.5}, Q: Point{X: 9.5, Y: 0.5}},
+		{P: Point{X: 2, Y: 0}, Q: Point{X: 2.1, Y: 10}},
+	}
+	tris := ConstrainedDelaunayTriangles(pts, cons, nil, NonZero)
+	hull := ConvexHull(pts)
+	assert.InDelta(t, hull.Area(), trianglesArea(tris), 1e-6)
+	for _, l := range cons {
+		assertCoversSegment(t, tris, l)
+	}
+	for _, tri := range tris {
+		assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+	}
+}
+
+func TestConstrainedDelaunayTriangles_boundary(t *testing.T) {
+	// a letter-like shape: an annulus with a slot, and a feature line inside
+	outer := Circle{Center: Point{X: 0, Y: 0}, Radius: 10}.ToCurve(60)
+	inner := Circle{Center: Point{X: 0, Y: 0}, Radius: 5}.ToCurve(30)
+	inner.Reverse()
+	region := []Curve{outer, inner}
+	feature := Line{P: Point{X: 6, Y: -2}, Q: Point{X: 9, Y: 2}}
+	rng := rand.New(rand.NewSource(1))
+	var sites []Point
+	for i := 0; i < 200; i++ {
+		sites = append(sites, Point{X: rng.Float64()*24 - 12, Y: rng.Float64()*24 - 12})
+	}
+	tris := ConstrainedDelaunayTriangles(sites, []Line{feature}, region, NonZero)
+	require.NotEmpty(t, tris)
+	want := math.Abs(outer.SignedArea()) - math.Abs(inner.SignedArea())
+	assert.InDelta(t, want, trianglesArea(tris), 1e-6)
+	assertCoversSegment(t, tris, feature)
+	for i := range outer.Points {
+		assertCoversSegment(t, tris, Line{P: outer.Points[i], Q: outer.Points[(i+1)%len(outer.Points)]})
+	}
+	for i := range inner.Points {
+		assertCoversSegment(t, tris, Line{P: inner.Points[i], Q: inner.Points[(i+1)%len(inner.Points)]})
+	}
+}
+
+func TestConstrainedDelaunayTriangles_concavePolygon(t *testing.T) {
+	u := Curve{Closed: true, Points: []Point{
+		{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 3}, {X: 2, Y: 3},
+		{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 3}, {X: 0, Y: 3},
+	}}
+	tris := ConstrainedDelaunayTriangles(nil, nil, []Curve{u}, NonZero)
+	assert.Len(t, tris, 6)
+	assert.InDelta(t, 7, trianglesArea(tris), Smol)
+	assert.Empty(t, ConstrainedDelaunayTriangles([]Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}, nil, nil, NonZero))
+}
@@ -0,0 +1,238 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// Clipper cuts open polylines against a region described by closed curves (outer
+// boundaries and holes, interpreted with a fill rule). The region may be non-convex,
+// self-intersecting or have holes. Building a Clipper indexes the region once, so it
+// pays to reuse one when clipping many curves against the same shape.
+type Clipper struct {
+	g    *planarGraph
+	idx  *windingIndex
+	rule FillRule
+}
+
+// NewClipper prepares a region for clipping. Every curve is treated as closed.
+func NewClipper(region []Curve, rule FillRule) *Clipper {
+	segs := appendCurveSegments(nil, region, 0)
+	if len(segs) == 0 {
+		return &Clipper{rule: rule}
+	}
+	g := newPlanarGraph(segs)
+	return &Clipper{g: g, idx: newWindingIndex(g), rule: rule}
+}
+
+// ClipCurve returns the pieces of c that lie inside the region (or outside it, when
+// inside is false), in the order they occur along c. Pieces that run along the region
+// boundary count as inside. A closed curve that doesn't cross the boundary is
+// returned whole and still closed; otherwise all pieces are open.
+func (cl *Clipper) ClipCurve(c Curve, inside bool) []Curve {
+	pts := dedupeConsecutivePoints(c.Points)
+	if c.Closed && len(pts) > 1 && pts[0].IsEqual(pts[len(pts)-1]) {
+		pts = pts[:len(pts)-1]
+	}
+	if len(pts) == 0 {
+		return nil
+	}
+	if len(pts) == 1 {
+		if cl.contains(pts[0]) == inside {
+			return []Curve{{Points: pts}}
+		}
+		return nil
+	}
+	if c.Closed {
+		pts = append(pts, pts[0])
+	}
+
+	// walk the polyline, cutting each segment where it meets the boundary
+	var pieces []Curve
+	var cur []Point
+	allKept := true
+	for i := 0; i+1 < len(pts); i++ {
+		cuts := cl.cuts(pts[i], pts[i+1])
+		for k := 0; k+1 < len(cuts); k++ {
+			a, b := cuts[k], cuts[k+1]
+			if cl.keepSpan(a, b, inside) {
+				if len(cur) == 0 {
+					cur = append(cur, a)
+				}
+				cur = append(cur, b)
+				continue
+			}
+			allKept = false
+			if len(cur) > 1 {
+				pieces = append(pieces, Curve{Points: cur})
+			}
+			cur = nil
+		}
+	}
+	if len(cur) > 1 {
+		pieces = append(pieces, Curve{Points: cur})
+	}
+	if !c.Closed {
+		return pieces
+	}
+	if allKept {
+		return []Curve{{Points: pts[:len(pts)-1], Closed: true}}
+	}
+	// a closed curve starts and ends at the same point, so a piece running through
+	// the start is split in two; join the halves
+	if len(pieces) > 1 {
+		first, last := pieces[0], pieces[len(pieces)-1]
+		if first.Points[0].IsEqual(pts[0]) && last.Points[len(last.Points)-1].IsEqual(pts[0]) {
+			joined := append(append([]Point(nil), last.Points...), first.Points[1:]...)
+			pieces = append([]Curve{{Points: joined}}, pieces[1:len(pieces)-1]...)
+		}
+	}
+	return pieces
+}
+
+// ClipLine returns the pieces of l that lie inside the region (or outside it, when
+// inside is false), ordered from l.P to l.Q
+func (cl *Clipper) ClipLine(l Line, inside bool) []Line {
+	var out []Line
+	for _, c := range cl.ClipCurve(Curve{Points: []Point{l.P, l.Q}}, inside) {
+		// a piece may hold several collinear points where it passed the boundary
+		out = append(out, Line{P: c.Points[0], Q: c.Points[len(c.Points)-1]})
+	}
+	return out
+}
+
+// ClipCurve returns the pieces of c inside (or outside) the region bounded by the
+// given closed curves, see [Clipper.ClipCurve]
+func ClipCurve(c Curve, region []Curve, rule FillRule, inside bool) []Curve {
+	return NewClipper(region, rule).ClipCurve(c, inside)
+}
+
+// ClipLines returns the pieces of all the lines inside (or outside) the region bounded
+// by the given closed curves, see [Clipper.ClipLine]
+func ClipLines(lines []Line, region []Curve, rule FillRule, inside bool) []Line {
+	cl := NewClipper(region, rule)
+	var out []Line
+	for _, l := range lines {
+		out = append(out, cl.ClipLine(l, inside)...)
+	}
+	return out
+}
+
+// cuts returns p, q and the points where segment p-q meets the boundary, in order
+func (cl *Clipper) cuts(p, q Point) []Point {
+	out := []Point{p, q}
+	if cl.g == nil {
+		return out
+	}
+	d := Vec2FromPoints(p, q)
+	lenSq := d.Dot(d)
+	type cut struct {
+		t float64
+		p Point
+	}
+	cuts := []cut{{0, p}, {1, q}}
+	for _, e := range cl.candidateEdges(p, q) {
+		edge := cl.g.edges[e]
+		hits, n := segmentIntersections(p, q, cl.g.pts[edge.a], cl.g.pts[edge.b], cl.g.eps)
+		for k := 0; k < n; k++ {
+			t := Vec2FromPoints(p, hits[k]).Dot(d) / lenSq
+			if t > 0 && t < 1 {
+				cuts = append(cuts, cut{t, hits[k]})
+			}
+		}
+	}
+	if len(cuts) == 2 {
+		return out
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].t < cuts[j].t })
+	out = out[:0]
+	for _, c := range cuts {
+		if len(out) > 0 && Distance(out[len(out)-1], c.p) <= cl.g.eps {
+			if c.t == 1 {
+				out[len(out)-1] = q
+			}
+			continue
+		}
+		out = append(out, c.p)
+	}
+	if len(out) == 1 {
+		out = append(out, q)
+	}
+	return out
+}
+
+// candidateEdges returns the boundary edges that may meet segment p-q, from whichever
+// of the two bucket indexes the segment spans fewer buckets of
+func (cl *Clipper) candidateEdges(p, q Point) []int {
+	if cl.g == nil {
+		return nil
+	}
+	eb, lo, hi := cl.idx.byY, math.Min(p.Y, q.Y), math.Max(p.Y, q.Y)
+	x0, x1 := math.Min(p.X, q.X), math.Max(p.X, q.X)
+	if cl.idx.byX.bucket(x1)-cl.idx.byX.bucket(x0) < eb.bucket(hi)-eb.bucket(lo) {
+		eb, lo, hi = cl.idx.byX, x0, x1
+	}
+	first, last := eb.bucket(lo-cl.g.eps), eb.bucket(hi+cl.g.eps)
+	if first == last {
+		return eb.buckets[first]
+	}
+	seen := make(map[int]bool)
+	var out []int
+	for k := first; k <= last; k++ {
+		for _, e := range eb.buckets[k] {
+			if !seen[e] {
+				seen[e] = true
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// keepSpan decides whether the piece a-b, which doesn't cross the boundary, is kept
+func (cl *Clipper) keepSpan(a, b Point, inside bool) bool {
+	m := Midpoint(a, b)
+	if cl.g == nil {
+		return !inside
+	}
+	if cl.onBoundary(m) {
+		// a piece along the boundary is inside if the region is on either side of it
+		n := Vec2FromPoints(a, b).UnitNormal().Scale(8 * cl.g.eps)
+		left := Point{X: m.X - n.X, Y: m.Y - n.Y}
+		right := Point{X: m.X + n.X, Y: m.Y + n.Y}
+		return (cl.contains(left) || cl.contains(right)) == inside
+	}
+	return cl.contains(m) == inside
+}
+
+// contains applies the fill rule to the winding number of the region around p
+func (cl *Clipper) contains(p Point) bool {
+	if cl.g == nil {
+		return false
+	}
+	w := 0
+	for _, e := range cl.idx.byY.buckets[cl.idx.byY.bucket(p.Y)] {
+		edge := cl.g.edges[e]
+		if edge.delta[0] != 0 {
+			w += edge.delta[0] * windingContribution(cl.g.pts[edge.a], cl.g.pts[edge.b], p)
+		}
+	}
+	return cl.rule.inside(w)
+}
+
+// onBoundary reports whether p is within the snapping tolerance of a boundary edge
+func (cl *Clipper) onBoundary(p Point) bool {
+	if cl.g == nil {
+		return false
+	}
+	for _, e := range cl.candidateEdges(p, p) {
+		edge := cl.g.edges[e]
+		if edge.delta[0] == 0 {
+			continue
+		}
+		if (Line{P: cl.g.pts[edge.a], Q: cl.g.pts[edge.b]}).SDF(p) <= cl.g.eps {
+			return true
+		}
+	}
+	return false
+}
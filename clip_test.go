@@ -0,0 +1,150 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func linesLength(lines []Line) float64 {
+	var sum float64
+	for _, l := range lines {
+		sum += l.Length()
+	}
+	return sum
+}
+
+func curvesLength(curves []Curve) float64 {
+	var sum float64
+	for _, c := range curves {
+		sum += c.Length()
+	}
+	return sum
+}
+
+func TestClipLine_nonConvex(t *testing.T) {
+	// a U shape: the 3x3 square with the middle column open at the top
+	u := Curve{Closed: true, Points: []Point{
+		{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 3}, {X: 2, Y: 3},
+		{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 3}, {X: 0, Y: 3},
+	}}
+	cl := NewClipper([]Curve{u}, NonZero)
+
+	in := cl.ClipLine(Line{P: Point{X: -1, Y: 2}, Q: Point{X: 4, Y: 2}}, true)
+	require.Len(t, in, 2)
+	assert.InDelta(t, 0, in[0].P.X, Smol)
+	assert.InDelta(t, 1, in[0].Q.X, Smol)
+	assert.InDelta(t, 2, in[1].P.X, Smol)
+	assert.InDelta(t, 3, in[1].Q.X, Smol)
+
+	out := cl.ClipLine(Line{P: Point{X: -1, Y: 2}, Q: Point{X: 4, Y: 2}}, false)
+	require.Len(t, out, 3)
+	assert.InDelta(t, 3, linesLength(out), Smol)
+
+	// the bottom of the U is crossed once
+	in = cl.ClipLine(Line{P: Point{X: -1, Y: 0.5}, Q: Point{X: 4, Y: 0.5}}, true)
+	require.Len(t, in, 1)
+	assert.InDelta(t, 3, in[0].Length(), Smol)
+
+	// entirely outside
+	assert.Empty(t, cl.ClipLine(Line{P: Point{X: 5, Y: 5}, Q: Point{X: 6, Y: 6}}, true))
+}
+
+func TestClipLine_holes(t *testing.T) {
+	outer := square(0, 0, 4)
+	hole := square(1, 1, 2)
+	hole.Reverse()
+	region := []Curve{outer, hole}
+
+	for _, rule := range []FillRule{EvenOdd, NonZero} {
+		in := ClipLines([]Line{{P: Point{X: -1, Y: 2}, Q: Point{X: 5, Y: 2}}}, region, rule, true)
+		require.Len(t, in, 2)
+		assert.InDelta(t, 2, linesLength(in), Smol)
+	}
+
+	// with both boundaries counterclockwise, only even-odd sees a hole
+	same := []Curve{outer, square(1, 1, 2)}
+	l := []Line{{P: Point{X: -1, Y: 2}, Q: Point{X: 5, Y: 2}}}
+	assert.Len(t, ClipLines(l, same, EvenOdd, true), 2)
+	assert.Len(t, ClipLines(l, same, NonZero, true), 1)
+}
+
+func TestClipLine_alongBoundary(t *testing.T) {
+	region := []Curve{square(0, 0, 2)}
+	in := ClipLines([]Line{{P: Point{X: -1, Y: 0}, Q: Point{X: 3, Y: 0}}}, region, NonZero, true)
+	require.Len(t, in, 1)
+	assert.InDelta(t, 2, in[0].Length(), Smol)
+	out := ClipLines([]Line{{P: Point{X: -1, Y: 0}, Q: Point{X: 3, Y: 0}}}, region, NonZero, false)
+	assert.Len(t, out, 2)
+	assert.InDelta(t, 2, linesLength(out), Smol)
+}
+
+func TestClipCurve_lengthsAddUp(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	star := Curve{Closed: true}
+	for i := 0; i < 14; i++ {
+		r := 3.0
+		if i%2 == 1 {
+			r = 1.2
+		}
+		a := float64(i) * Tau / 14
+		star.Points = append(star.Points, Point{X: r * math.Cos(a), Y: r * math.Sin(a)})
+	}
+	cl := NewClipper([]Curve{star}, NonZero)
+	for trial := 0; trial < 20; trial++ {
+		walk := Curve{}
+		p := Point{X: rng.Float64()*8 - 4, Y: rng.Float64()*8 - 4}
+		for i := 0; i < 30; i++ {
+			walk.Points = append(walk.Points, p)
+			p = Point{X: p.X + rng.Float64() - 0.5, Y: p.Y + rng.Float64() - 0.5}
+		}
+		in := cl.ClipCurve(walk, true)
+		out := cl.ClipCurve(walk, false)
+		assert.InDelta(t, walk.Length(), curvesLength(in)+curvesLength(out), 1e-6)
+		for _, c := range in {
+			for i := 0; i+1 < len(c.Points); i++ {
+				m := Midpoint(c.Points[i], c.Points[i+1])
+				assert.True(t, star.ContainsPoint(m, NonZero))
+			}
+		}
+		for _, c := range out {
+			for i := 0; i+1 < len(c.Points); i++ {
+				m := Midpoint(c.Points[i], c.Points[i+1])
+				assert.False(t, star.ContainsPoint(m, NonZero) && !star.PointOnEdge(m, Smol))
+			}
+		}
+	}
+}
+
+func TestClipCurve_closed(t *testing.T) {
+	circle := Circle{Center: Point{X: 0, Y: 0}, Radius: 1}.ToCurve(64)
+	region := []Curve{square(0, -2, 4)}
+
+	// the right half of the circle, as one piece even though it runs through the start
+	in := ClipCurve(circle, region, NonZero, true)
+	require.Len(t, in, 1)
+	assert.False(t, in[0].Closed)
+	assert.InDelta(t, circle.Length()/2, in[0].Length(), 1e-6)
+
+	// a curve that stays inside comes back whole
+	small := Circle{Center: Point{X: 2, Y: 0}, Radius: 1}.ToCurve(32)
+	in = ClipCurve(small, region, NonZero, true)
+	require.Len(t, in, 1)
+	assert.True(t, in[0].Closed)
+	assert.Len(t, in[0].Points, len(small.Points))
+	assert.Empty(t, ClipCurve(small, region, NonZero, false))
+}
+
+func TestClipper_noEdges(t *testing.T) {
+	// single points make a region with no boundary, so nothing is inside it
+	cl := NewClipper([]Curve{{Points: []Point{{0, 0}}}, {Points: []Point{{5, 5}}}}, NonZero)
+	assert.False(t, cl.onBoundary(Point{X: 0, Y: 0}))
+	assert.False(t, cl.contains(Point{X: 1, Y: 1}))
+	assert.Empty(t, cl.candidateEdges(Point{X: 0, Y: 0}, Point{X: 5, Y: 5}))
+	l := Line{P: Point{X: -1, Y: 0}, Q: Point{X: 6, Y: 5}}
+	assert.Empty(t, cl.ClipLine(l, true))
+	assert.Equal(t, []Line{l}, cl.ClipLine(l, false))
+}
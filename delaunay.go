@@ -24,18 +24,39 @@ func dedupeSitesDelaunay(sites []Point) []Point {
 	return out
 }
 
+// DelaunayMethod selects the algorithm used by [DelaunayTrianglesWith]
+type DelaunayMethod int
+
+const (
+	// DelaunaySweepHull adds the sites in order of distance from a seed triangle,
+	// joining each to the visible part of the hull; O(n log n)
+	DelaunaySweepHull DelaunayMethod = iota
+	// DelaunayBowyerWatson inserts the sites one by one into a super-triangle,
+	// scanning all triangles for each; O(n²)
+	DelaunayBowyerWatson
+)
+
 // DelaunayTriangles returns the Delaunay triangulation of the unique sites (duplicate
-// coordinates are merged). Each output [Triangle] is oriented counterclockwise. The
-// result is nil if there are fewer than three unique sites, or if no triangles remain
-// after removing the artificial super-triangle (e.g. all sites collinear).
+// coordinates are merged). Each output [Triangle] is oriented counterclockwise, and
+// together they cover the convex hull of the sites. Where four or more sites lie on a
+// common circle, they are split the same way every time, by the tie-break of
+// [delaunayInCircle]. The result is nil if there are fewer than three unique sites,
+// or if they are all collinear.
 //
-// Implementation: Bowyer–Watson with a bounding super-triangle. The straightforward
-// formulation scans all triangles for each insertion, which is O(n²) in the worst case.
-// Divide-and-conquer or sweepline Delaunay constructions achieve O(n log n) time but
-// are more intricate to implement and maintain. CCW winding is not a property of any
-// particular asymptotic class: it is applied when building each triangle, and any
-// correct Delaunay routine can enforce the same convention.
+// Implementation: a radial sweep hull, which takes O(n log n) time and handles a
+// million sites. See [DelaunayTrianglesWith] to choose another algorithm.
 func DelaunayTriangles(sites []Point) []Triangle {
+	return DelaunayTrianglesWith(sites, DelaunaySweepHull)
+}
+
+// DelaunayTrianglesWith is [DelaunayTriangles] with a choice of algorithm. Both give
+// the same triangles, up to their order.
+//
+// Bowyer–Watson in its straightforward formulation scans all triangles for each
+// insertion, which is O(n²) in the worst case; it is kept for comparison. CCW winding
+// is not a property of any particular asymptotic class: it is applied when building
+// each triangle, and any correct Delaunay routine can enforce the same convention.
+func DelaunayTrianglesWith(sites []Point, method DelaunayMethod) []Triangle {
 	if len(sites) == 0 {
 		return nil
 	}
@@ -43,7 +64,10 @@ func DelaunayTriangles(sites []Point) []Triangle {
 	if len(unique) < 3 {
 		return nil
 	}
-	return bowyerWatson(unique)
+	if method == DelaunayBowyerWatson {
+		return bowyerWatson(unique)
+	}
+	return sweepHull(unique)
 }
 
 type triInt struct {
@@ -67,27 +91,6 @@ func edgeIntKey(i, j int) edgeInt {
 	return edgeInt{j, i}
 }
 
-func orient2(a, b, c Point) float64 {
-	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
-}
-
-// inCircumcircle reports whether p lies strictly inside the circumcircle of triangle ABC.
-// ABC may be clockwise or counterclockwise; degenerate (collinear) ABC yields false.
-func inCircumcircle(a, b, c, p Point) bool {
-	o := orient2(a, b, c)
-	if math.Abs(o) <= Smol {
-		return false
-	}
-	val := (a.X*a.X+a.Y*a.Y)*orient2(b, c, p) +
-		(b.X*b.X+b.Y*b.Y)*orient2(c, a, p) +
-		(c.X*c.X+c.Y*c.Y)*orient2(a, b, p) -
-		(p.X*p.X+p.Y*p.Y)*o
-	if o > 0 {
-		return val > Smol
-	}
-	return val < -Smol
-}
-
 func superTriangle(pts []Point) (Point, Point, Point) {
 	minX, minY := pts[0].X, pts[0].Y
 	maxX, maxY := pts[0].X, pts[0].Y
@@ -128,21 +131,43 @@ func ccwOrder(i, j, k int, pts []Point) (int, int, int) {
 	return i, k, j
 }
 
+// bowyerWatson inserts the sites into a super-triangle whose corners lie at
+// infinity, as in [newGhostTriangulation], so that thin triangles along the convex
+// hull are found too
 func bowyerWatson(unique []Point) []Triangle {
-	s0, s1, s2 := superTriangle(unique)
-	pts := make([]Point, 0, len(unique)+3)
-	pts = append(pts, s0, s1, s2)
-	pts = append(pts, unique...)
+	lo, hi := unique[0], unique[0]
+	for _, p := range unique {
+		lo = Point{X: math.Min(lo.X, p.X), Y: math.Min(lo.Y, p.Y)}
+		hi = Point{X: math.Max(hi.X, p.X), Y: math.Max(hi.Y, p.Y)}
+	}
+	mid := Midpoint(lo, hi)
+	pts := make([]ghostPoint, 0, len(unique)+3)
+	for _, d := range ghostDirs {
+		pts = append(pts, ghostPoint{p: mid, d: d})
+	}
+	for _, p := range unique {
+		pts = append(pts, ghostPoint{p: p})
+	}
+	inCircle := func(t triInt, k int) bool {
+		if t.i >= 3 && t.j >= 3 && t.k >= 3 {
+			return delaunayInCircle(pts[t.i].p, pts[t.j].p, pts[t.k].p, pts[k].p)
+		}
+		return ghostInCircle(pts[t.i], pts[t.j], pts[t.k], pts[k])
+	}
+	ccwOrder := func(i, j, k int) (int, int, int) {
+		if ghostOrient(pts[i], pts[j], pts[k]) >= 0 {
+			return i, j, k
+		}
+		return i, k, j
+	}
 
 	tris := []triInt{{0, 1, 2}}
 	n := len(pts)
 
 	for k := 3; k < n; k++ {
-		pk := pts[k]
 		var bad []triInt
 		for _, t := range tris {
-			a, b, c := pts[t.i], pts[t.j], pts[t.k]
-			if inCircumcircle(a, b, c, pk) {
+			if inCircle(t, k) {
 				bad = append(bad, t)
 			}
 		}
@@ -180,7 +205,7 @@ func bowyerWatson(unique []Point) []Triangle {
 				continue
 			}
 			i, j := e.a, e.b
-			ii, jj, kk := ccwOrder(i, j, k, pts)
+			ii, jj, kk := ccwOrder(i, j, k)
 			tris = append(tris, triInt{ii, jj, kk})
 		}
 	}
@@ -190,7 +215,7 @@ func bowyerWatson(unique []Point) []Triangle {
 		if t.i < 3 || t.j < 3 || t.k < 3 {
 			continue
 		}
-		a, b, c := pts[t.i], pts[t.j], pts[t.k]
+		a, b, c := pts[t.i].p, pts[t.j].p, pts[t.k].p
 		if orient2(a, b, c) < -Smol {
 			a, b = b, a
 		}
@@ -198,3 +223,45 @@ func bowyerWatson(unique []Point) []Triangle {
 	}
 	return out
 }
+
+// delaunayInCircle is [inCircumcircle] with ties broken as if each point were lifted
+// a little below the paraboloid, by far more the earlier it comes in (X, Y) order.
+// Of four cocircular points, the first sinks below the plane through the others: it
+// is inside their circle, and a point is inside a circle through it if the two lie
+// on opposite sides of the chord between the other two. This is a consistent
+// perturbation, so cocircular sites have a single Delaunay triangulation, which both
+// Delaunay methods find.
+func delaunayInCircle(a, b, c, p Point) bool {
+	o := orient2(a, b, c)
+	if o == 0 {
+		return false
+	}
+	if o < 0 {
+		a, b = b, a
+	}
+	if det := incircle(a, b, c, p); det != 0 {
+		return det > 0
+	}
+	switch first := minPoint(minPoint(a, b), minPoint(c, p)); first {
+	case p:
+		return true
+	case a:
+		return orient2(b, c, p) < 0
+	case b:
+		return orient2(c, a, p) < 0
+	default:
+		return orient2(a, b, p) < 0
+	}
+}
+
+// minPoint returns the first of p and q in (X, Y) order
+func minPoint(p, q Point) Point {
+	if pointBefore(q, p) {
+		return q
+	}
+	return p
+}
+
+func pointBefore(p, q Point) bool {
+	return p.X < q.X || (p.X == q.X && p.Y < q.Y)
+}
@@ -2,7 +2,9 @@ package gaul
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,27 +82,128 @@ func TestDelaunayTriangles_duplicatesMerged(t *testing.T) {
 	assert.InDelta(t, sum2, sum, 1e-9)
 }
 
+// triangleKey identifies a triangle by its corners, in any order
+func triangleKey(t Triangle) [3]Point {
+	k := [3]Point{t.A, t.B, t.C}
+	sort.Slice(k[:], func(i, j int) bool {
+		return k[i].X < k[j].X || (k[i].X == k[j].X && k[i].Y < k[j].Y)
+	})
+	return k
+}
+
+func TestDelaunayTrianglesWith_methodsAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	var random, thin, grid, circle []Point
+	for i := 0; i < 400; i++ {
+		random = append(random, Point{X: rng.NormFloat64(), Y: rng.NormFloat64()})
+	}
+	// many sites give slivers along the hull, with huge circumcircles
+	for i := 0; i < 2000; i++ {
+		thin = append(thin, Point{X: rng.Float64(), Y: rng.Float64()})
+	}
+	// cocircular sites, and rows of collinear hull sites
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 9; j++ {
+			grid = append(grid, Point{X: float64(i), Y: float64(j)})
+		}
+	}
+	for _, v := range []float64{-5, -4, -3, 0, 3, 4, 5} {
+		circle = append(circle, Point{X: v, Y: math.Sqrt(25 - v*v)}, Point{X: v, Y: -math.Sqrt(25 - v*v)})
+	}
+	for name, sites := range map[string][]Point{"random": random, "thin": thin, "grid": grid, "circle": circle} {
+		sweep := DelaunayTrianglesWith(sites, DelaunaySweepHull)
+		bw := DelaunayTrianglesWith(sites, DelaunayBowyerWatson)
+		require.NotEmpty(t, sweep, name)
+		keys, bwKeys := make(map[[3]Point]bool), make(map[[3]Point]bool)
+		for _, tri := range sweep {
+			assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0, name)
+			keys[triangleKey(tri)] = true
+		}
+		for _, tri := range bw {
+			assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0, name)
+			bwKeys[triangleKey(tri)] = true
+		}
+		assert.Len(t, bw, len(sweep), name)
+		assert.Equal(t, keys, bwKeys, name)
+	}
+}
+
+func TestDelaunayTriangles_coversHull(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	var sites []Point
+	for i := 0; i < 5000; i++ {
+		sites = append(sites, Point{X: rng.Float64() * 100, Y: rng.Float64() * 50})
+	}
+	tr := DelaunayTriangles(sites)
+	hull := ConvexHull(sites)
+	var area float64
+	for _, tri := range tr {
+		area += orient2(tri.A, tri.B, tri.C) / 2
+	}
+	assert.InDelta(t, hull.Area(), area, 1e-6)
+	// a triangulation of n points with h of them on the hull has 2n-2-h triangles
+	assert.Len(t, tr, 2*len(sites)-2-len(hull.Points))
+	for _, tri := range tr[:200] {
+		for _, p := range sites {
+			assert.False(t, inCircumcircle(tri.A, tri.B, tri.C, p))
+		}
+	}
+}
+
+func TestDelaunayTriangles_degenerate(t *testing.T) {
+	// a grid has many sites on common circles, and rows of collinear hull sites
+	var grid []Point
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 15; j++ {
+			grid = append(grid, Point{X: float64(i), Y: float64(j)})
+		}
+	}
+	tr := DelaunayTriangles(grid)
+	var area float64
+	for _, tri := range tr {
+		assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+		area += orient2(tri.A, tri.B, tri.C) / 2
+	}
+	assert.InDelta(t, 19*14, area, 1e-9)
+	assert.Len(t, tr, 2*19*14)
+
+	assert.Nil(t, DelaunayTriangles([]Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}))
+}
+
 // BenchmarkDelaunayTriangles measures [DelaunayTriangles] with deterministic
 // pseudo-random sites in a fixed square window per sub-benchmark size.
 //
 // Example: go test -bench=BenchmarkDelaunayTriangles -run=^$ -benchmem -count=5
 func BenchmarkDelaunayTriangles(b *testing.B) {
-	// Bowyer–Watson is roughly O(n²); keep large sizes out of default bench runs.
-	sizes := []int{10, 50, 100, 500, 1000, 2500}
+	// Bowyer–Watson is roughly O(n²); keep large sizes out of its runs.
+	sizes := []int{10, 50, 100, 500, 1000, 2500, 50000, 1000000}
+	methods := []struct {
+		name    string
+		method  DelaunayMethod
+		maxSize int
+	}{
+		{"sweephull", DelaunaySweepHull, 1000000},
+		{"bowyerwatson", DelaunayBowyerWatson, 2500},
+	}
 	for _, n := range sizes {
 		rng := rand.New(rand.NewSource(42))
 		sites := make([]Point, n)
 		for i := 0; i < n; i++ {
 			sites[i] = Point{X: rng.Float64() * 1000, Y: rng.Float64() * 1000}
 		}
-		b.Run(fmt.Sprintf("sites_%d", n), func(b *testing.B) {
-			b.ReportAllocs()
-			var tr []Triangle
-			b.ResetTimer()
-			for i := 0; i < b.N; i++ {
-				tr = DelaunayTriangles(sites)
+		for _, m := range methods {
+			if n > m.maxSize {
+				continue
 			}
-			_ = tr
-		})
+			b.Run(fmt.Sprintf("%s/sites_%d", m.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				var tr []Triangle
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					tr = DelaunayTrianglesWith(sites, m.method)
+				}
+				_ = tr
+			})
+		}
 	}
 }
@@ -0,0 +1,425 @@
+package gaul
+
+import (
+	"math"
+
+	"github.com/tdewolff/canvas"
+)
+
+// An Ellipse represented by a center point, the semi-axes Rx and Ry, and the angle
+// (in radians, counterclockwise from the x axis) of the Rx axis.
+//
+// Points on an ellipse are addressed by their eccentric angle t, which gives the
+// point Center + Rx*cos(t)*u + Ry*sin(t)*v, with u the direction of the Rx axis and v
+// that of the Ry axis. For a circle this is the ordinary polar angle.
+type Ellipse struct {
+	Center Point
+	Rx     float64
+	Ry     float64
+	Angle  float64
+}
+
+// An Arc is the part of an ellipse from the eccentric angle Start through Sweep
+// radians, counterclockwise for a positive sweep and clockwise for a negative one.
+// Circular arcs are arcs of ellipses with equal radii.
+type Arc struct {
+	Ellipse Ellipse
+	Start   float64
+	Sweep   float64
+}
+
+// Ellipse functions
+
+// ToEllipse returns the circle as an ellipse with equal radii
+func (c Circle) ToEllipse() Ellipse {
+	return Ellipse{Center: c.Center, Rx: c.Radius, Ry: c.Radius}
+}
+
+// Draw draws the ellipse given a canvas context
+func (e Ellipse) Draw(ctx *canvas.Context) {
+	path := canvas.Ellipse(e.Rx, e.Ry).Transform(canvas.Identity.Rotate(Rad2Deg(e.Angle)))
+	ctx.DrawPath(e.Center.X, e.Center.Y, path)
+}
+
+// ToCurve calculates a curve that approximates the ellipse with a given resolution
+// (number of sides), with vertices evenly spaced in eccentric angle
+func (e Ellipse) ToCurve(resolution int) Curve {
+	points := make([]Point, resolution)
+	theta := Linspace(0, Tau, resolution, false)
+	for i, t := range theta {
+		points[i] = e.PointAtAngle(t)
+	}
+	return Curve{Points: points, Closed: true}
+}
+
+// PointAtAngle returns the point on the ellipse at the given eccentric angle
+func (e Ellipse) PointAtAngle(t float64) Point {
+	return e.fromLocal(Point{X: e.Rx * math.Cos(t), Y: e.Ry * math.Sin(t)})
+}
+
+// fromLocal maps a point from the frame of the ellipse axes to the plane
+func (e Ellipse) fromLocal(p Point) Point {
+	sin, cos := math.Sincos(e.Angle)
+	return Point{
+		X: e.Center.X + p.X*cos - p.Y*sin,
+		Y: e.Center.Y + p.X*sin + p.Y*cos,
+	}
+}
+
+// toLocal maps a point from the plane to the frame of the ellipse axes
+func (e Ellipse) toLocal(p Point) Point {
+	sin, cos := math.Sincos(e.Angle)
+	dx, dy := p.X-e.Center.X, p.Y-e.Center.Y
+	return Point{X: dx*cos + dy*sin, Y: -dx*sin + dy*cos}
+}
+
+// angleOf returns the eccentric angle of the point of the ellipse closest in direction
+// to p, as seen from the center after scaling the ellipse to a circle
+func (e Ellipse) angleOf(p Point) float64 {
+	l := e.toLocal(p)
+	return math.Atan2(l.Y*e.Rx, l.X*e.Ry)
+}
+
+// ContainsPoint determines if a point lies inside the ellipse, including the boundary
+func (e Ellipse) ContainsPoint(p Point) bool {
+	return e.SDF(p) <= Smol
+}
+
+// PointOnEdge determines if a point lies on the boundary of the ellipse
+func (e Ellipse) PointOnEdge(p Point) bool {
+	return math.Abs(e.SDF(p)) <= Smol
+}
+
+// Area returns the area of the ellipse
+func (e Ellipse) Area() float64 {
+	return Pi * e.Rx * e.Ry
+}
+
+// Perimeter returns the length of the boundary of the ellipse, computed to full
+// precision with the arithmetic-geometric mean
+func (e Ellipse) Perimeter() float64 {
+	a, b := math.Abs(e.Rx), math.Abs(e.Ry)
+	if a < b {
+		a, b = b, a
+	}
+	if b == 0 {
+		return 4 * a
+	}
+	// Perimeter = 2*Pi/AGM(a, b) * (a^2 - sum 2^(n-1) c_n^2)
+	x, y := a, b
+	sum := (a*a - b*b) / 2
+	pow := 0.5
+	for x-y > 1e-15*x {
+		c := (x - y) / 2
+		x, y = (x+y)/2, math.Sqrt(x*y)
+		pow *= 2
+		sum += pow * c * c
+	}
+	return Tau * (a*a - sum) / x
+}
+
+// Boundary returns the smallest rect that contains all points on the ellipse
+func (e Ellipse) Boundary() Rect {
+	sin, cos := math.Sincos(e.Angle)
+	hx := math.Hypot(e.Rx*cos, e.Ry*sin)
+	hy := math.Hypot(e.Rx*sin, e.Ry*cos)
+	return Rect{X: e.Center.X - hx, Y: e.Center.Y - hy, W: 2 * hx, H: 2 * hy}
+}
+
+// ClosestPoint returns the closest point on the ellipse to the given point
+func (e Ellipse) ClosestPoint(p Point) Point {
+	return e.fromLocal(closestOnAxisEllipse(math.Abs(e.Rx), math.Abs(e.Ry), e.toLocal(p)))
+}
+
+// SDF calculates the signed distance from a point to the ellipse, which is negative
+// inside
+func (e Ellipse) SDF(p Point) float64 {
+	l := e.toLocal(p)
+	a, b := math.Abs(e.Rx), math.Abs(e.Ry)
+	d := Distance(l, closestOnAxisEllipse(a, b, l))
+	if a > 0 && b > 0 && (l.X*l.X)/(a*a)+(l.Y*l.Y)/(b*b) < 1 {
+		return -d
+	}
+	return d
+}
+
+// closestOnAxisEllipse returns the point of the ellipse x^2/a^2 + y^2/b^2 = 1 closest
+// to p. The point is found in the first quadrant by iterating on the center of
+// curvature (the evolute) of the current estimate, then mirrored back.
+func closestOnAxisEllipse(a, b float64, p Point) Point {
+	px, py := math.Abs(p.X), math.Abs(p.Y)
+	var x, y float64
+	switch {
+	case a == 0 && b == 0:
+	case a == 0:
+		y = math.Min(py, b)
+	case b == 0:
+		x = math.Min(px, a)
+	default:
+		tx, ty := math.Sqrt2/2, math.Sqrt2/2
+		for i := 0; i < 8; i++ {
+			x, y = a*tx, b*ty
+			ex := (a*a - b*b) * tx * tx * tx / a
+			ey := (b*b - a*a) * ty * ty * ty / b
+			r := math.Hypot(x-ex, y-ey)
+			q := math.Hypot(px-ex, py-ey)
+			if q == 0 {
+				break
+			}
+			tx = Clamp(0, 1, ((px-ex)*r/q+ex)/a)
+			ty = Clamp(0, 1, ((py-ey)*r/q+ey)/b)
+			t := math.Hypot(tx, ty)
+			tx, ty = tx/t, ty/t
+		}
+		x, y = a*tx, b*ty
+	}
+	return Point{X: math.Copysign(x, p.X), Y: math.Copysign(y, p.Y)}
+}
+
+// Arc functions
+
+// ArcThroughPoints returns the circular arc that starts at a, passes through b and ends
+// at c. It reports false if the points are collinear.
+func ArcThroughPoints(a, b, c Point) (Arc, bool) {
+	turn := orient2(a, b, c)
+	if turn == 0 {
+		return Arc{}, false
+	}
+	circle := circleThrough(a, b, c)
+	start := math.Atan2(a.Y-circle.Center.Y, a.X-circle.Center.X)
+	end := math.Atan2(c.Y-circle.Center.Y, c.X-circle.Center.X)
+	sweep := math.Mod(end-start, Tau)
+	if turn > 0 {
+		// a counterclockwise turn at b goes around the circle counterclockwise
+		if sweep <= 0 {
+			sweep += Tau
+		}
+	} else if sweep >= 0 {
+		sweep -= Tau
+	}
+	return Arc{Ellipse: circle.ToEllipse(), Start: start, Sweep: sweep}, true
+}
+
+// ArcTangentToLines returns the circular arc of the given radius that rounds off the
+// corner where the lines through l1 and l2 meet (a fillet). The corner is the angle
+// between the rays from their intersection towards the far ends of l1 and l2; the arc
+// runs from its tangent point on l1 to its tangent point on l2. It reports false if the
+// lines are parallel or the radius isn't positive.
+func ArcTangentToLines(l1, l2 Line, radius float64) (Arc, bool) {
+	d1, d2 := Vec2FromPoints(l1.P, l1.Q), Vec2FromPoints(l2.P, l2.Q)
+	cross := d1.X*d2.Y - d1.Y*d2.X
+	if radius <= 0 || cross == 0 {
+		return Arc{}, false
+	}
+	t := Vec2FromPoints(l1.P, l2.P)
+	s := (t.X*d2.Y - t.Y*d2.X) / cross
+	corner := Vec2FromPoint(l1.P).Add(d1.Scale(s)).ToPoint()
+	far := func(l Line) Vec2 {
+		if SquaredDistance(corner, l.P) > SquaredDistance(corner, l.Q) {
+			return Vec2FromPoints(corner, l.P).Normalize()
+		}
+		return Vec2FromPoints(corner, l.Q).Normalize()
+	}
+	u1, u2 := far(l1), far(l2)
+	half := math.Acos(Clamp(-1, 1, u1.Dot(u2))) / 2
+	if half == 0 || half == Pi/2 {
+		return Arc{}, false
+	}
+	o := Vec2FromPoint(corner)
+	bisector := u1.Add(u2).Normalize()
+	center := o.Add(bisector.Scale(radius / math.Sin(half))).ToPoint()
+	tangent := radius / math.Tan(half)
+	p1 := o.Add(u1.Scale(tangent)).ToPoint()
+	p2 := o.Add(u2.Scale(tangent)).ToPoint()
+	start := math.Atan2(p1.Y-center.Y, p1.X-center.X)
+	end := math.Atan2(p2.Y-center.Y, p2.X-center.X)
+	// the fillet is always the short way round
+	sweep := math.Remainder(end-start, Tau)
+	return Arc{Ellipse: Circle{Center: center, Radius: radius}.ToEllipse(), Start: start, Sweep: sweep}, true
+}
+
+// End returns the eccentric angle at which the arc ends
+func (a Arc) End() float64 {
+	return a.Start + a.Sweep
+}
+
+// StartPoint returns the first point of the arc
+func (a Arc) StartPoint() Point {
+	return a.Ellipse.PointAtAngle(a.Start)
+}
+
+// EndPoint returns the last point of the arc
+func (a Arc) EndPoint() Point {
+	return a.Ellipse.PointAtAngle(a.End())
+}
+
+// PointAtAngle returns the point of the underlying ellipse at the given eccentric
+// angle, which need not be on the arc
+func (a Arc) PointAtAngle(t float64) Point {
+	return a.Ellipse.PointAtAngle(t)
+}
+
+// Draw draws the arc given a canvas context. A filled arc is closed by its chord.
+func (a Arc) Draw(ctx *canvas.Context) {
+	e := a.Ellipse
+	path := canvas.EllipticalArc(e.Rx, e.Ry, Rad2Deg(e.Angle), Rad2Deg(a.Start), Rad2Deg(a.End()))
+	start := a.StartPoint()
+	ctx.DrawPath(start.X, start.Y, path)
+}
+
+// ToCurve calculates an open curve that approximates the arc with a given resolution
+// (number of segments), with vertices evenly spaced in eccentric angle
+func (a Arc) ToCurve(resolution int) Curve {
+	if resolution < 1 {
+		resolution = 1
+	}
+	points := make([]Point, resolution+1)
+	for i := range points {
+		points[i] = a.Ellipse.PointAtAngle(a.Start + a.Sweep*float64(i)/float64(resolution))
+	}
+	return Curve{Points: points}
+}
+
+// Length returns the arc length. Arcs of ellipses are integrated numerically.
+func (a Arc) Length() float64 {
+	rx, ry := math.Abs(a.Ellipse.Rx), math.Abs(a.Ellipse.Ry)
+	sweep := math.Abs(a.Sweep)
+	if rx == ry {
+		return rx * sweep
+	}
+	if sweep >= Tau {
+		full := math.Floor(sweep / Tau)
+		rest := Arc{Ellipse: a.Ellipse, Start: a.Start, Sweep: sweep - full*Tau}
+		return full*a.Ellipse.Perimeter() + rest.Length()
+	}
+	speed := func(t float64) float64 {
+		sin, cos := math.Sincos(t)
+		return math.Hypot(rx*sin, ry*cos)
+	}
+	// composite Simpson's rule; the integrand is smooth and periodic
+	n := 2 * int(math.Ceil(sweep/Tau*256))
+	if n < 2 {
+		n = 2
+	}
+	h := a.Sweep / float64(n)
+	sum := speed(a.Start) + speed(a.End())
+	for i := 1; i < n; i++ {
+		w := 2.0
+		if i%2 == 1 {
+			w = 4
+		}
+		sum += w * speed(a.Start+float64(i)*h)
+	}
+	return math.Abs(sum * h / 3)
+}
+
+// containsAngle reports whether the eccentric angle t lies within the sweep of the arc
+func (a Arc) containsAngle(t float64) bool {
+	if math.Abs(a.Sweep) >= Tau {
+		return true
+	}
+	d := math.Mod(t-a.Start, Tau)
+	if a.Sweep >= 0 {
+		if d < 0 {
+			d += Tau
+		}
+		return d <= a.Sweep
+	}
+	if d > 0 {
+		d -= Tau
+	}
+	return d >= a.Sweep
+}
+
+// ContainsPoint determines if a point lies in the region bounded by the arc and its
+// chord, including the boundary
+func (a Arc) ContainsPoint(p Point) bool {
+	if !a.Ellipse.ContainsPoint(p) {
+		return false
+	}
+	if math.Abs(a.Sweep) >= Tau {
+		return true
+	}
+	// the region is the part of the ellipse on the same side of the chord as the
+	// middle of the arc
+	s, e := a.StartPoint(), a.EndPoint()
+	mid := a.Ellipse.PointAtAngle(a.Start + a.Sweep/2)
+	side := orient2(s, e, mid)
+	return orient2(s, e, p)*side >= 0 || (Line{P: s, Q: e}).SDF(p) <= Smol
+}
+
+// Boundary returns the smallest rect that contains all points on the arc
+func (a Arc) Boundary() Rect {
+	e := a.Ellipse
+	sin, cos := math.Sincos(e.Angle)
+	candidates := []float64{
+		math.Atan2(-e.Ry*sin, e.Rx*cos), // extremes in x
+		math.Atan2(e.Ry*cos, e.Rx*sin),  // extremes in y
+	}
+	pts := []Point{a.StartPoint(), a.EndPoint()}
+	for _, t := range candidates {
+		for _, u := range []float64{t, t + Pi} {
+			if a.containsAngle(u) {
+				pts = append(pts, e.PointAtAngle(u))
+			}
+		}
+	}
+	return (&Curve{Points: pts}).Boundary()
+}
+
+// ClosestPoint returns the closest point on the arc to the given point
+func (a Arc) ClosestPoint(p Point) Point {
+	e := a.Ellipse
+	q := e.ClosestPoint(p)
+	if a.containsAngle(e.angleOf(q)) {
+		return q
+	}
+	best := a.StartPoint()
+	if SquaredDistance(p, a.EndPoint()) < SquaredDistance(p, best) {
+		best = a.EndPoint()
+	}
+	if e.Rx == e.Ry {
+		// on a circle the distance only grows away from the closest point
+		return best
+	}
+	// an ellipse may have another local minimum within the sweep: search near the
+	// closest of a set of samples
+	const samples = 64
+	h := a.Sweep / samples
+	k := 0
+	for i := 1; i <= samples; i++ {
+		if SquaredDistance(p, e.PointAtAngle(a.Start+float64(i)*h)) <
+			SquaredDistance(p, e.PointAtAngle(a.Start+float64(k)*h)) {
+			k = i
+		}
+	}
+	lo := a.Start + float64(k-1)*h
+	hi := a.Start + float64(k+1)*h
+	if k == 0 {
+		lo = a.Start
+	}
+	if k == samples {
+		hi = a.End()
+	}
+	// golden section search
+	const invPhi = 0.6180339887498949
+	for i := 0; i < 60; i++ {
+		m1 := hi - (hi-lo)*invPhi
+		m2 := lo + (hi-lo)*invPhi
+		if SquaredDistance(p, e.PointAtAngle(m1)) < SquaredDistance(p, e.PointAtAngle(m2)) {
+			hi = m2
+		} else {
+			lo = m1
+		}
+	}
+	if c := e.PointAtAngle((lo + hi) / 2); SquaredDistance(p, c) < SquaredDistance(p, best) {
+		best = c
+	}
+	return best
+}
+
+// SDF calculates the distance from a point to the arc. An arc doesn't enclose
+// anything, so unlike the SDF of closed shapes the distance is never negative.
+func (a Arc) SDF(p Point) float64 {
+	return Distance(p, a.ClosestPoint(p))
+}
@@ -0,0 +1,141 @@
+package gaul
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertPointNear(t *testing.T, want, got Point) {
+	t.Helper()
+	assert.InDelta(t, want.X, got.X, Smol, "x of %v", got)
+	assert.InDelta(t, want.Y, got.Y, Smol, "y of %v", got)
+}
+
+func TestEllipse_Perimeter(t *testing.T) {
+	for _, e := range []Ellipse{
+		{Rx: 1, Ry: 1},
+		{Rx: 3, Ry: 1, Angle: 0.4},
+		{Rx: 0.5, Ry: 4},
+		{Rx: 10, Ry: 0.01},
+	} {
+		approx := e.ToCurve(20000)
+		assert.InEpsilon(t, approx.Length(), e.Perimeter(), 1e-7)
+	}
+	assert.InDelta(t, 8, Ellipse{Rx: 2}.Perimeter(), Smol)
+	assert.InDelta(t, Tau*3, Circle{Radius: 3}.Circumference(), Smol)
+}
+
+func TestEllipse_ClosestPoint(t *testing.T) {
+	e := Ellipse{Center: Point{X: 1, Y: -2}, Rx: 5, Ry: 2, Angle: 0.7}
+	boundary := e.ToCurve(4096)
+	probes := []Point{{X: 1, Y: -2}, {X: 8, Y: 3}, {X: -3, Y: -2.5}, {X: 2, Y: -1}, {X: 1, Y: 6}}
+	for _, p := range probes {
+		q := e.ClosestPoint(p)
+		assert.True(t, e.PointOnEdge(q), "%v is not on the ellipse", q)
+		// no sample of the boundary is closer
+		best := math.Inf(1)
+		for _, s := range boundary.Points {
+			best = math.Min(best, Distance(p, s))
+		}
+		assert.LessOrEqual(t, Distance(p, q), best+1e-9)
+		assert.InDelta(t, best, Distance(p, q), 1e-2)
+		assert.InDelta(t, Distance(p, q), math.Abs(e.SDF(p)), 1e-9)
+	}
+	assert.Less(t, e.SDF(e.Center), 0.0)
+	assert.True(t, e.ContainsPoint(Point{X: 2, Y: -1}))
+	assert.False(t, e.ContainsPoint(Point{X: 8, Y: 3}))
+
+	c := Circle{Center: Point{X: 1, Y: 1}, Radius: 2}
+	assert.InDelta(t, 3, c.SDF(Point{X: 6, Y: 1}), Smol)
+	assertPointNear(t, Point{X: 1, Y: 3}, c.ClosestPoint(Point{X: 1, Y: 5}))
+}
+
+func TestEllipse_Boundary(t *testing.T) {
+	e := Ellipse{Center: Point{X: 3, Y: 4}, Rx: 4, Ry: 1, Angle: Pi / 6}
+	want := e.ToCurve(10000)
+	b, w := e.Boundary(), want.Boundary()
+	assert.InDelta(t, w.X, b.X, 1e-6)
+	assert.InDelta(t, w.Y, b.Y, 1e-6)
+	assert.InDelta(t, w.W, b.W, 1e-6)
+	assert.InDelta(t, w.H, b.H, 1e-6)
+
+	arc := Arc{Ellipse: e, Start: -0.3, Sweep: 2}
+	arcCurve := arc.ToCurve(10000)
+	b, w = arc.Boundary(), arcCurve.Boundary()
+	assert.InDelta(t, w.X, b.X, 1e-6)
+	assert.InDelta(t, w.Y, b.Y, 1e-6)
+	assert.InDelta(t, w.W, b.W, 1e-6)
+	assert.InDelta(t, w.H, b.H, 1e-6)
+}
+
+func TestArc_Length(t *testing.T) {
+	c := Arc{Ellipse: Circle{Radius: 2}.ToEllipse(), Start: 1, Sweep: -Pi}
+	assert.InDelta(t, 2*Pi, c.Length(), Smol)
+
+	e := Arc{Ellipse: Ellipse{Rx: 3, Ry: 1}, Start: 0.5, Sweep: 4}
+	approx := e.ToCurve(20000)
+	assert.InEpsilon(t, approx.Length(), e.Length(), 1e-7)
+	full := Arc{Ellipse: Ellipse{Rx: 3, Ry: 1}, Sweep: Tau + 1}
+	assert.InEpsilon(t, e.Ellipse.Perimeter()+Arc{Ellipse: e.Ellipse, Sweep: 1}.Length(), full.Length(), 1e-9)
+}
+
+func TestArcThroughPoints(t *testing.T) {
+	a, b, c := Point{X: 1, Y: 0}, Point{X: 0, Y: 1}, Point{X: -1, Y: 0}
+	arc, ok := ArcThroughPoints(a, b, c)
+	require.True(t, ok)
+	assert.InDelta(t, Pi, arc.Sweep, Smol)
+	assertPointNear(t, a, arc.StartPoint())
+	assertPointNear(t, c, arc.EndPoint())
+	assertPointNear(t, b, arc.PointAtAngle(arc.Start+arc.Sweep/2))
+
+	// clockwise, and more than half the circle
+	arc, ok = ArcThroughPoints(Point{X: 0, Y: 1}, Point{X: 1, Y: 0}, Point{X: -1, Y: 0})
+	require.True(t, ok)
+	assert.InDelta(t, -1.5*Pi, arc.Sweep, Smol)
+	assertPointNear(t, Point{X: -1, Y: 0}, arc.EndPoint())
+	assert.True(t, arc.ContainsPoint(Point{X: 0, Y: -0.9}))
+	assert.False(t, arc.ContainsPoint(Point{X: -0.6, Y: 0.6}))
+
+	_, ok = ArcThroughPoints(Point{}, Point{X: 1, Y: 1}, Point{X: 2, Y: 2})
+	assert.False(t, ok)
+}
+
+func TestArcTangentToLines(t *testing.T) {
+	// a right-angled corner at (4, 0)
+	l1 := Line{P: Point{X: 0, Y: 0}, Q: Point{X: 4, Y: 0}}
+	l2 := Line{P: Point{X: 4, Y: 0}, Q: Point{X: 4, Y: 4}}
+	arc, ok := ArcTangentToLines(l1, l2, 1)
+	require.True(t, ok)
+	assertPointNear(t, Point{X: 3, Y: 1}, arc.Ellipse.Center)
+	assertPointNear(t, Point{X: 3, Y: 0}, arc.StartPoint())
+	assertPointNear(t, Point{X: 4, Y: 1}, arc.EndPoint())
+	assert.InDelta(t, Pi/2, arc.Sweep, Smol)
+
+	// the fillet is tangent to both lines
+	l3 := Line{P: Point{X: 14, Y: -1}, Q: Point{X: 2, Y: 5}}
+	arc, ok = ArcTangentToLines(l1, l3, 0.5)
+	require.True(t, ok)
+	assert.InDelta(t, 0.5, Line{P: Point{X: -100, Y: 0}, Q: Point{X: 100, Y: 0}}.SDF(arc.Ellipse.Center), Smol)
+	assert.InDelta(t, 0.5, l3.SDF(arc.Ellipse.Center), Smol)
+	assert.Less(t, math.Abs(arc.Sweep), Pi)
+
+	_, ok = ArcTangentToLines(l1, Line{P: Point{X: 0, Y: 1}, Q: Point{X: 1, Y: 1}}, 1)
+	assert.False(t, ok)
+}
+
+func TestArc_ClosestPoint(t *testing.T) {
+	arc := Arc{Ellipse: Ellipse{Rx: 4, Ry: 1}, Start: 0.2, Sweep: 2.5}
+	samples := arc.ToCurve(20000).Points
+	for _, p := range []Point{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: -4, Y: -1}, {X: 1, Y: -3}, {X: 0, Y: 3}} {
+		q := arc.ClosestPoint(p)
+		best := math.Inf(1)
+		for _, s := range samples {
+			best = math.Min(best, Distance(p, s))
+		}
+		assert.InDelta(t, best, Distance(p, q), 1e-3, "closest point to %v", p)
+		assert.InDelta(t, Distance(p, q), arc.SDF(p), Smol)
+	}
+}
@@ -277,21 +277,21 @@ func (l Line) Length() float64 {
 
 // Intersects determines if two lines intersect each other
 func (l Line) Intersects(k Line) bool {
-	a1 := l.Q.X - l.P.X
-	b1 := k.P.X - k.Q.X
-	c1 := k.P.X - l.P.X
-	a2 := l.Q.Y - l.P.Y
-	b2 := k.P.Y - k.Q.Y
-	c2 := k.P.Y - l.P.Y
-	d := a1*b2 - a2*b1
-	if d == 0 {
-		// lines are parallel
+	// the orientation tests are exact, so touching and crossing segments are never
+	// missed through rounding
+	o1, o2 := orient2(l.P, l.Q, k.P), orient2(l.P, l.Q, k.Q)
+	if o1 == 0 && o2 == 0 {
+		// collinear segments are parallel
 		return false
 	}
-	// Cramer's rule
-	s := (c1*b2 - c2*b1) / d
-	t := (a1*c2 - a2*c1) / d
-	return s >= 0 && t >= 0 && s <= 1 && t <= 1
+	o3, o4 := orient2(k.P, k.Q, l.P), orient2(k.P, k.Q, l.Q)
+	return straddles(o1, o2) && straddles(o3, o4)
+}
+
+// straddles reports whether two orientations with respect to a line put the points on
+// opposite sides of it, or either of them on it
+func straddles(a, b float64) bool {
+	return (a <= 0 && b >= 0) || (a >= 0 && b <= 0)
 }
 
 // ParallelTo determines if two lines are parallel
@@ -605,6 +605,112 @@ func (c *Curve) Area() float64 {
 	return 0.5 * math.Abs(area)
 }
 
+// SignedArea calculates the area of a closed curve, positive when its points run
+// counterclockwise and negative when they run clockwise (for +Y up)
+func (c *Curve) SignedArea() float64 {
+	if !c.Closed {
+		return math.NaN()
+	}
+	return polygonSignedArea(c.Points)
+}
+
+// IsCCW determines if a closed curve runs counterclockwise (for +Y up)
+func (c *Curve) IsCCW() bool {
+	return c.SignedArea() > 0
+}
+
+// EnsureCCW reverses a closed curve that runs clockwise, so it runs counterclockwise
+func (c *Curve) EnsureCCW() {
+	if c.SignedArea() < 0 {
+		c.Reverse()
+	}
+}
+
+// WindingNumber calculates how many times the curve winds counterclockwise around a
+// point, treating the curve as closed. Points on the curve itself get the winding
+// number of the region on one side of it; use PointOnEdge to detect them.
+func (c *Curve) WindingNumber(p Point) int {
+	return polygonWinding(c.Points, p)
+}
+
+// ContainsPoint determines if a point lies inside the curve under the given fill
+// rule, including points within Smol of the boundary, as [Ellipse.ContainsPoint]
+// does, so that points computed on the boundary count as inside. The curve is treated
+// as closed and may be non-convex or self-intersecting. Use
+// [Curve.ContainsPointWithin] for curves far from unit scale.
+func (c *Curve) ContainsPoint(p Point, rule FillRule) bool {
+	return c.ContainsPointWithin(p, rule, Smol)
+}
+
+// ContainsPointWithin is [Curve.ContainsPoint] with points within a distance tol of
+// the boundary counted as inside; with a tol of 0 only the fill rule decides.
+func (c *Curve) ContainsPointWithin(p Point, rule FillRule, tol float64) bool {
+	if rule.inside(c.WindingNumber(p)) {
+		return true
+	}
+	if tol <= 0 {
+		return false
+	}
+	closed := Curve{Points: c.Points, Closed: true}
+	return closed.PointOnEdge(p, tol)
+}
+
+// PointOnEdge determines if a point lies within a distance tol of the curve
+func (c *Curve) PointOnEdge(p Point, tol float64) bool {
+	n := len(c.Points)
+	if n == 1 {
+		return Distance(c.Points[0], p) <= tol
+	}
+	count := n - 1
+	if c.Closed {
+		count = n
+	}
+	for i := 0; i < count; i++ {
+		l := Line{P: c.Points[i], Q: c.Points[(i+1)%n]}
+		if l.P.IsEqual(l.Q) {
+			if Distance(l.P, p) <= tol {
+				return true
+			}
+			continue
+		}
+		if l.SDF(p) <= tol {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonSignedArea is the shoelace area of a polygon, positive when counterclockwise
+func polygonSignedArea(pts []Point) float64 {
+	var s float64
+	n := len(pts)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		s += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
+	}
+	return 0.5 * s
+}
+
+// ensurePolygonCCW reverses pts in place if the polygon is clockwise
+func ensurePolygonCCW(pts []Point) []Point {
+	if polygonSignedArea(pts) < 0 {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	return pts
+}
+
+// polygonWinding returns the winding number of the closed polygon around p
+func polygonWinding(poly []Point, p Point) int {
+	w := 0
+	n := len(poly)
+	for i := 0; i < n; i++ {
+		w += windingContribution(poly[i], poly[(i+1)%n], p)
+	}
+	return w
+}
+
 // Centroid returns the centroid for the curve
 func (c *Curve) Centroid() Point {
 	if !c.Closed {
@@ -711,6 +817,31 @@ func (c Circle) Boundary() Rect {
 	return Rect{X: minX, Y: minY, W: 2 * r, H: 2 * r}
 }
 
+// Circumference returns the length of the boundary of the circle
+func (c Circle) Circumference() float64 {
+	return Tau * c.Radius
+}
+
+// PointAtAngle returns the point on the circle at the given angle (in radians) from the
+// center
+func (c Circle) PointAtAngle(theta float64) Point {
+	return Point{X: c.Center.X + c.Radius*math.Cos(theta), Y: c.Center.Y + c.Radius*math.Sin(theta)}
+}
+
+// ClosestPoint returns the closest point on the circle to the given point
+func (c Circle) ClosestPoint(p Point) Point {
+	if p.IsEqual(c.Center) {
+		return c.PointAtAngle(0)
+	}
+	return c.PointAtAngle(math.Atan2(p.Y-c.Center.Y, p.X-c.Center.X))
+}
+
+// SDF calculates the signed distance from a point to the circle, which is negative
+// inside
+func (c Circle) SDF(p Point) float64 {
+	return Distance(c.Center, p) - c.Radius
+}
+
 // Rect functions
 
 // ContainsPoint determines if a point lies within a rectangle
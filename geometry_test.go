@@ -453,3 +453,123 @@ func TestTriangleSDF(t *testing.T) {
 		}
 	}
 }
+
+func TestCurve_ContainsPoint(t *testing.T) {
+	// U shape: a 3x3 square with a 1x2 notch cut from the top middle, wound clockwise
+	u := Curve{Closed: true, Points: []Point{
+		{0, 0}, {0, 3}, {1, 3}, {1, 1}, {2, 1}, {2, 3}, {3, 3}, {3, 0},
+	}}
+	tests := []struct {
+		name     string
+		point    Point
+		expected bool
+	}{
+		{"inside left arm", Point{X: 0.5, Y: 2.5}, true},
+		{"inside base", Point{X: 1.5, Y: 0.5}, true},
+		{"in the notch", Point{X: 1.5, Y: 2}, false},
+		{"outside", Point{X: 4, Y: 1}, false},
+		{"on vertex", Point{X: 1, Y: 1}, true},
+		{"on edge", Point{X: 1.5, Y: 1}, true},
+		{"on closing edge", Point{X: 1.5, Y: 0}, true},
+		{"level with vertices", Point{X: -1, Y: 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, rule := range []FillRule{NonZero, EvenOdd} {
+				got := u.ContainsPoint(tt.point, rule)
+				if got != tt.expected {
+					t.Errorf("Curve.ContainsPoint(%v, %v) = %v, want %v", tt.point, rule, got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestCurve_ContainsPointWithin(t *testing.T) {
+	// a square 1e-6 across, for which Smol is too coarse
+	sq := Curve{Closed: true, Points: []Point{{0, 0}, {1e-6, 0}, {1e-6, 1e-6}, {0, 1e-6}}}
+	near := Point{X: 2e-6, Y: 5e-7}
+	if !sq.ContainsPoint(near, NonZero) {
+		t.Errorf("Curve.ContainsPoint(%v) = false, want true within Smol", near)
+	}
+	if sq.ContainsPointWithin(near, NonZero, 1e-9) {
+		t.Errorf("Curve.ContainsPointWithin(%v, 1e-9) = true, want false", near)
+	}
+	if !sq.ContainsPointWithin(Point{X: 1e-6 + 5e-10, Y: 5e-7}, NonZero, 1e-9) {
+		t.Errorf("Curve.ContainsPointWithin should include points within tol")
+	}
+	if !sq.ContainsPointWithin(Point{X: 5e-7, Y: 5e-7}, EvenOdd, 0) {
+		t.Errorf("Curve.ContainsPointWithin should include interior points")
+	}
+}
+
+func TestCurve_WindingNumber(t *testing.T) {
+	// a pentagram, whose center is wound twice
+	var star Curve
+	star.Closed = true
+	for i := 0; i < 5; i++ {
+		a := Pi/2 + 2*Tau*float64(i)/5
+		star.AddPoint(math.Cos(a), math.Sin(a))
+	}
+	center := Point{X: 0, Y: 0}
+	tip := Point{X: 0, Y: 0.8}
+	if w := star.WindingNumber(center); w != 2 {
+		t.Errorf("winding number at center = %d, want 2", w)
+	}
+	if w := star.WindingNumber(tip); w != 1 {
+		t.Errorf("winding number in tip = %d, want 1", w)
+	}
+	if !star.ContainsPoint(center, NonZero) || star.ContainsPoint(center, EvenOdd) {
+		t.Errorf("center should be inside under NonZero only")
+	}
+	if !star.ContainsPoint(tip, EvenOdd) {
+		t.Errorf("tip should be inside under EvenOdd")
+	}
+
+	star.Reverse()
+	if w := star.WindingNumber(center); w != -2 {
+		t.Errorf("winding number of reversed star at center = %d, want -2", w)
+	}
+	if w := star.WindingNumber(Point{X: 2, Y: 0}); w != 0 {
+		t.Errorf("winding number outside = %d, want 0", w)
+	}
+}
+
+func TestCurve_PointOnEdge(t *testing.T) {
+	c := Curve{Points: []Point{{0, 0}, {1, 0}, {1, 1}}}
+	p := Point{X: 0.5, Y: 0.01}
+	if !c.PointOnEdge(p, 0.02) {
+		t.Errorf("%v should be within 0.02 of the curve", p)
+	}
+	if c.PointOnEdge(p, 0.005) {
+		t.Errorf("%v should not be within 0.005 of the curve", p)
+	}
+	diag := Point{X: 0.5, Y: 0.5}
+	if c.PointOnEdge(diag, 0.1) {
+		t.Errorf("an open curve has no closing segment")
+	}
+	c.Closed = true
+	if !c.PointOnEdge(diag, 0.1) {
+		t.Errorf("%v should be on the closing segment", diag)
+	}
+}
+
+func TestCurve_Orientation(t *testing.T) {
+	c := Rect{X: 0, Y: 0, W: 2, H: 1}.ToCurve()
+	if !Equalf(c.SignedArea(), 2) || !c.IsCCW() {
+		t.Errorf("rect curve: signed area %f, want 2 and counterclockwise", c.SignedArea())
+	}
+	c.Reverse()
+	if !Equalf(c.SignedArea(), -2) || c.IsCCW() {
+		t.Errorf("reversed rect curve: signed area %f, want -2 and clockwise", c.SignedArea())
+	}
+	c.EnsureCCW()
+	if !Equalf(c.SignedArea(), c.Area()) {
+		t.Errorf("EnsureCCW: signed area %f, want %f", c.SignedArea(), c.Area())
+	}
+
+	open := Curve{Points: []Point{{0, 0}, {1, 0}, {1, 1}}}
+	if !math.IsNaN(open.SignedArea()) || open.IsCCW() {
+		t.Errorf("open curves have no signed area")
+	}
+}
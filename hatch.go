@@ -0,0 +1,193 @@
+package gaul
+
+import "math"
+
+// HatchStyle selects how [Hatch] fills a region
+type HatchStyle int
+
+const (
+	// HatchParallel fills with separate straight lines
+	HatchParallel HatchStyle = iota
+	// HatchZigzag joins the lines of a parallel fill end to end wherever the
+	// connecting stroke stays inside the region, so there are fewer pen lifts
+	HatchZigzag
+	// HatchConcentric fills with insets of the boundary, one spacing apart
+	HatchConcentric
+)
+
+// HatchOptions configures [Hatch]. The zero value (apart from Spacing) gives
+// horizontal parallel lines and the even-odd fill rule.
+type HatchOptions struct {
+	Style HatchStyle
+	// Angle is the direction of the lines in radians
+	Angle float64
+	// Spacing is the distance between neighbouring lines (or insets)
+	Spacing float64
+	// Cross adds a second set of lines in the direction CrossAngle. It has no effect
+	// on concentric fills.
+	Cross      bool
+	CrossAngle float64
+	// Rule picks the parts of the region that lines and insets are kept in
+	Rule FillRule
+}
+
+// Hatch fills a region described by closed curves with strokes a pen plotter can draw,
+// returned as curves. Parallel and zigzag fills give open curves; concentric fills give
+// closed ones.
+//
+// Lines are laid out on a grid anchored at the origin rather than at the region, so
+// neighbouring regions hatched with the same angle and spacing line up.
+func Hatch(region []Curve, opts HatchOptions) []Curve {
+	if opts.Spacing <= 0 {
+		return nil
+	}
+	if opts.Style == HatchConcentric {
+		return hatchConcentric(region, opts)
+	}
+	cl := NewClipper(region, opts.Rule)
+	angles := []float64{opts.Angle}
+	if opts.Cross {
+		angles = append(angles, opts.CrossAngle)
+	}
+	var out []Curve
+	for _, angle := range angles {
+		rows := hatchRows(cl, region, angle, opts.Spacing)
+		if opts.Style == HatchZigzag {
+			out = append(out, zigzagChains(cl, rows)...)
+			continue
+		}
+		for _, row := range rows {
+			for _, l := range row {
+				out = append(out, Curve{Points: []Point{l.P, l.Q}})
+			}
+		}
+	}
+	return out
+}
+
+// HatchLines returns the parallel lines at the given angle and spacing that fill a
+// region described by closed curves, see [Hatch]
+func HatchLines(region []Curve, rule FillRule, angle, spacing float64) []Line {
+	if spacing <= 0 {
+		return nil
+	}
+	var out []Line
+	for _, row := range hatchRows(NewClipper(region, rule), region, angle, spacing) {
+		out = append(out, row...)
+	}
+	return out
+}
+
+// Hatch fills the closed curve with strokes, see [Hatch]
+func (c *Curve) Hatch(opts HatchOptions) []Curve {
+	return Hatch([]Curve{*c}, opts)
+}
+
+// hatchRows clips evenly spaced lines at the given angle to the region. Rows are
+// ordered across the lines and the pieces of a row along them.
+func hatchRows(cl *Clipper, region []Curve, angle, spacing float64) [][]Line {
+	u := Vec2{X: math.Cos(angle), Y: math.Sin(angle)}
+	v := Vec2{X: -u.Y, Y: u.X}
+	uLo, uHi := math.Inf(1), math.Inf(-1)
+	vLo, vHi := math.Inf(1), math.Inf(-1)
+	for _, c := range region {
+		for _, p := range c.Points {
+			pu, pv := Vec2FromPoint(p).Dot(u), Vec2FromPoint(p).Dot(v)
+			uLo, uHi = math.Min(uLo, pu), math.Max(uHi, pu)
+			vLo, vHi = math.Min(vLo, pv), math.Max(vHi, pv)
+		}
+	}
+	if uLo > uHi {
+		return nil
+	}
+	// start and end a little outside the region so the ends are always cut
+	margin := math.Max(spacing, uHi-uLo)
+	uLo, uHi = uLo-margin, uHi+margin
+	// lines through (or within rounding of) the extreme points would only touch the
+	// boundary
+	first := int(math.Floor(vLo/spacing+Smol)) + 1
+	last := int(math.Ceil(vHi/spacing-Smol)) - 1
+	var rows [][]Line
+	for k := first; k <= last; k++ {
+		base := v.Scale(float64(k) * spacing)
+		l := Line{P: base.Add(u.Scale(uLo)).ToPoint(), Q: base.Add(u.Scale(uHi)).ToPoint()}
+		if row := cl.ClipLine(l, true); len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// zigzagChains links the pieces of consecutive rows into polylines. Each chain grows
+// from its last point to the nearest unused piece of the next row that it can reach
+// with a stroke inside the region, so consecutive pieces alternate direction.
+func zigzagChains(cl *Clipper, rows [][]Line) []Curve {
+	used := make([][]bool, len(rows))
+	for i, row := range rows {
+		used[i] = make([]bool, len(row))
+	}
+	var out []Curve
+	for r := range rows {
+		for i := range rows[r] {
+			if used[r][i] {
+				continue
+			}
+			used[r][i] = true
+			chain := []Point{rows[r][i].P, rows[r][i].Q}
+			for next := r + 1; next < len(rows); next++ {
+				tail := chain[len(chain)-1]
+				best, bestD, flip := -1, math.Inf(1), false
+				for j, l := range rows[next] {
+					if used[next][j] {
+						continue
+					}
+					for _, end := range []bool{false, true} {
+						start := l.P
+						if end {
+							start = l.Q
+						}
+						d := SquaredDistance(tail, start)
+						if d < bestD && len(cl.ClipLine(Line{P: tail, Q: start}, false)) == 0 {
+							best, bestD, flip = j, d, end
+						}
+					}
+				}
+				if best < 0 {
+					break
+				}
+				used[next][best] = true
+				l := rows[next][best]
+				if flip {
+					l = Line{P: l.Q, Q: l.P}
+				}
+				chain = append(chain, l.P, l.Q)
+			}
+			out = append(out, Curve{Points: chain})
+		}
+	}
+	return out
+}
+
+// hatchConcentric insets the region by whole multiples of the spacing until nothing
+// is left
+func hatchConcentric(region []Curve, opts HatchOptions) []Curve {
+	var pts []Point
+	for _, c := range region {
+		pts = append(pts, c.Points...)
+	}
+	if len(pts) == 0 {
+		return nil
+	}
+	bounds := (&Curve{Points: pts}).Boundary()
+	// no inset can go deeper than half the smaller side of the bounding box
+	maxK := int(math.Min(bounds.W, bounds.H)/2/opts.Spacing) + 1
+	var out []Curve
+	for k := 1; k <= maxK; k++ {
+		insets := OffsetCurves(region, opts.Rule, -float64(k)*opts.Spacing, OffsetOptions{})
+		if len(insets) == 0 {
+			return out
+		}
+		out = append(out, insets...)
+	}
+	return out
+}
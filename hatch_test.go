@@ -0,0 +1,88 @@
+package gaul
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertInsideRegion checks that every segment of the curves stays in the region
+func assertInsideRegion(t *testing.T, curves []Curve, region []Curve, rule FillRule) {
+	cl := NewClipper(region, rule)
+	for _, c := range curves {
+		for _, piece := range cl.ClipCurve(c, false) {
+			assert.InDelta(t, 0, piece.Length(), 1e-6)
+		}
+	}
+}
+
+func TestHatch_parallel(t *testing.T) {
+	region := []Curve{square(0, 0, 4)}
+	lines := HatchLines(region, EvenOdd, 0, 1)
+	require.Len(t, lines, 3)
+	for i, l := range lines {
+		assert.InDelta(t, float64(i+1), l.P.Y, Smol)
+		assert.InDelta(t, float64(i+1), l.Q.Y, Smol)
+		assert.InDelta(t, 4, l.Length(), Smol)
+	}
+
+	curves := Hatch(region, HatchOptions{Spacing: 1, Cross: true, CrossAngle: Pi / 2})
+	assert.Len(t, curves, 6)
+	assert.InDelta(t, 24, curvesLength(curves), 1e-6)
+
+	assert.Empty(t, Hatch(region, HatchOptions{}))
+	assert.Empty(t, Hatch(nil, HatchOptions{Spacing: 1}))
+}
+
+func TestHatch_holes(t *testing.T) {
+	hole := square(3, 3, 4)
+	hole.Reverse()
+	region := []Curve{square(0, 0, 10), hole}
+	lines := HatchLines(region, NonZero, Pi/4, 0.5)
+	var total float64
+	for _, l := range lines {
+		total += l.Length()
+	}
+	// a fine hatch covers about area/spacing
+	assert.InEpsilon(t, (100-16)/0.5, total, 0.05)
+	curves := make([]Curve, len(lines))
+	for i, l := range lines {
+		curves[i] = Curve{Points: []Point{l.P, l.Q}}
+	}
+	assertInsideRegion(t, curves, region, NonZero)
+}
+
+func TestHatch_zigzag(t *testing.T) {
+	region := []Curve{square(0, 0, 4)}
+	zz := Hatch(region, HatchOptions{Style: HatchZigzag, Spacing: 1})
+	require.Len(t, zz, 1)
+	require.Len(t, zz[0].Points, 6)
+	// consecutive lines alternate direction, joined along the sides
+	assert.InDelta(t, 12+2, zz[0].Length(), 1e-6)
+
+	// a U shape needs a separate chain for one of its arms
+	u := []Curve{{Closed: true, Points: []Point{
+		{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 3}, {X: 2, Y: 3},
+		{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 3}, {X: 0, Y: 3},
+	}}}
+	opts := HatchOptions{Style: HatchZigzag, Spacing: 0.25, Angle: 0.1}
+	zz = Hatch(u, opts)
+	opts.Style = HatchParallel
+	parallel := Hatch(u, opts)
+	assert.Less(t, len(zz), len(parallel)/4)
+	assert.GreaterOrEqual(t, len(zz), 2)
+	assertInsideRegion(t, zz, u, EvenOdd)
+}
+
+func TestHatch_concentric(t *testing.T) {
+	c := square(0, 0, 10)
+	rings := c.Hatch(HatchOptions{Style: HatchConcentric, Spacing: 1})
+	require.Len(t, rings, 4)
+	for i, r := range rings {
+		assert.True(t, r.Closed)
+		side := 10 - 2*float64(i+1)
+		assert.InDelta(t, side*side, math.Abs(r.SignedArea()), 1e-6)
+	}
+}
@@ -0,0 +1,85 @@
+package gaul
+
+import "sort"
+
+// ConvexHull returns the convex hull of points as a closed, counterclockwise curve,
+// using Andrew's monotone chain algorithm in O(n log n). Duplicate points and points
+// lying on a hull edge are left out. With fewer than three non-collinear points the
+// curve holds the unique extreme points (one or two of them).
+func ConvexHull(points []Point) Curve {
+	pts := dedupeSitesDelaunay(points)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].X != pts[j].X {
+			return pts[i].X < pts[j].X
+		}
+		return pts[i].Y < pts[j].Y
+	})
+	if len(pts) < 3 {
+		return Curve{Points: pts, Closed: true}
+	}
+	hull := make([]Point, 0, 2*len(pts))
+	// lower chain left to right, then upper chain right to left
+	for _, p := range pts {
+		for len(hull) >= 2 && orient2(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(pts) - 2; i >= 0; i-- {
+		p := pts[i]
+		for len(hull) >= lower && orient2(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// the last point repeats the first
+	hull = hull[:len(hull)-1]
+	return Curve{Points: hull, Closed: true}
+}
+
+// AlphaShape returns the concave hull of points for a given alpha, built from the
+// Delaunay triangles whose circumradius is at most alpha. Small values of alpha
+// follow the points closely and may break the shape into several components or open
+// holes in it; as alpha grows the shape approaches the convex hull.
+//
+// The result uses the same conventions as [BooleanCurves]: closed curves with outer
+// boundaries counterclockwise and holes clockwise. Points not covered by any kept
+// triangle are not part of the shape.
+func AlphaShape(points []Point, alpha float64) []Curve {
+	if alpha <= 0 {
+		return nil
+	}
+	tris := DelaunayTriangles(points)
+	index := make(map[Point]int)
+	var pts []Point
+	vertex := func(p Point) int {
+		if i, ok := index[p]; ok {
+			return i
+		}
+		index[p] = len(pts)
+		pts = append(pts, p)
+		return len(pts) - 1
+	}
+	// an edge is on the boundary if exactly one kept triangle uses it; the
+	// counterclockwise triangles give each such edge the shape on its left
+	count := make(map[edgeInt]int)
+	var directed [][2]int
+	for _, t := range tris {
+		if t.CircumcircleRadius() > alpha {
+			continue
+		}
+		a, b, c := vertex(t.A), vertex(t.B), vertex(t.C)
+		for _, e := range [][2]int{{a, b}, {b, c}, {c, a}} {
+			count[edgeIntKey(e[0], e[1])]++
+			directed = append(directed, e)
+		}
+	}
+	var boundary [][2]int
+	for _, e := range directed {
+		if count[edgeIntKey(e[0], e[1])] == 1 {
+			boundary = append(boundary, e)
+		}
+	}
+	return loopsToCurves(pts, traceLoops(pts, boundary))
+}
@@ -0,0 +1,90 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvexHull_grid(t *testing.T) {
+	var pts []Point
+	for i := 0; i <= 4; i++ {
+		for j := 0; j <= 4; j++ {
+			pts = append(pts, Point{X: float64(i), Y: float64(j)})
+		}
+	}
+	pts = append(pts, pts[7], pts[0])
+	hull := ConvexHull(pts)
+	assert.True(t, hull.Closed)
+	assert.Equal(t, []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, hull.Points)
+}
+
+func TestConvexHull_random(t *testing.T) {
+	rng := NewRng(4)
+	pts := rng.UniformRandomPoints(500, Rect{X: 0, Y: 0, W: 10, H: 5})
+	hull := ConvexHull(pts)
+	require.GreaterOrEqual(t, len(hull.Points), 3)
+	assert.True(t, voronoiIsConvexCCW(hull.Points))
+	for _, p := range pts {
+		assert.True(t, hull.ContainsPoint(p, NonZero))
+	}
+
+	cells, err := VoronoiWithCurve(hull, pts[:50])
+	require.NoError(t, err)
+	var total float64
+	for _, c := range cells {
+		total += c.Area()
+	}
+	assert.InDelta(t, hull.Area(), total, 1e-6)
+}
+
+func TestConvexHull_degenerate(t *testing.T) {
+	assert.Empty(t, ConvexHull(nil).Points)
+	assert.Equal(t, []Point{{1, 1}}, ConvexHull([]Point{{1, 1}, {1, 1}}).Points)
+	collinear := []Point{{2, 2}, {0, 0}, {1, 1}, {3, 3}}
+	assert.Equal(t, []Point{{0, 0}, {3, 3}}, ConvexHull(collinear).Points)
+}
+
+func TestAlphaShape_ring(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	var pts []Point
+	for len(pts) < 2000 {
+		p := Point{X: 4*rng.Float64() - 2, Y: 4*rng.Float64() - 2}
+		if r := math.Hypot(p.X, p.Y); r >= 1 && r <= 2 {
+			pts = append(pts, p)
+		}
+	}
+	got := AlphaShape(pts, 0.3)
+	var outer, holes int
+	for _, c := range got {
+		if c.IsCCW() {
+			outer++
+		} else {
+			holes++
+		}
+	}
+	assert.Equal(t, 1, outer)
+	assert.Equal(t, 1, holes)
+	assert.InEpsilon(t, 3*Pi, signedAreaSum(got), 0.06)
+
+	hull := ConvexHull(pts)
+	got = AlphaShape(pts, 100)
+	require.Len(t, got, 1)
+	assert.InDelta(t, hull.Area(), got[0].Area(), 1e-9)
+}
+
+func TestAlphaShape_clusters(t *testing.T) {
+	rng := NewRng(8)
+	pts := rng.UniformRandomPoints(200, Rect{X: 0, Y: 0, W: 1, H: 1})
+	pts = append(pts, rng.UniformRandomPoints(200, Rect{X: 3, Y: 0, W: 1, H: 1})...)
+	got := AlphaShape(pts, 0.5)
+	require.Len(t, got, 2)
+	for _, c := range got {
+		assert.True(t, c.IsCCW())
+		assert.InDelta(t, 1, c.Area(), 0.2)
+	}
+	assert.Empty(t, AlphaShape(pts, 0))
+}
@@ -0,0 +1,505 @@
+package gaul
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// IncrementalDelaunay is the Delaunay triangulation of sites in a rectangle, with the
+// Voronoi diagram clipped to it, that sites can be inserted into, removed from and
+// moved within without a rebuild: each change only redoes the triangles around the
+// site, so animating many drifting sites costs little per frame. The triangles,
+// cells and neighbours are those that [DelaunayTriangles] and [NewVoronoiDiagram]
+// give for the current sites, up to order, even where four or more sites lie on a
+// common circle.
+//
+// Sites are referred to by the id that [IncrementalDelaunay.Insert] returns, which
+// stays theirs when they move and isn't reused after they are removed. Duplicate
+// sites share a cell, and neighbour lists give the lowest id among them.
+type IncrementalDelaunay struct {
+	bounds  Rect
+	tr      *triangulation
+	sites   []Point
+	vertex  []int        // vertex of each site, -1 once it is removed
+	ids     [][]int      // sites at each vertex, in increasing order
+	removed map[int]bool // sites removed since the last call to Changed
+}
+
+// NewIncrementalDelaunay returns an [IncrementalDelaunay] in bounds holding sites,
+// whose ids are their indices. Sites must lie inside or on bounds.
+func NewIncrementalDelaunay(bounds Rect, sites []Point) (*IncrementalDelaunay, error) {
+	if bounds.W <= 0 || bounds.H <= 0 {
+		return nil, errors.New("gaul NewIncrementalDelaunay: bounds width and height must be positive")
+	}
+	for i, p := range sites {
+		if !bounds.ContainsPoint(p) {
+			return nil, fmt.Errorf("gaul NewIncrementalDelaunay: site %d is not inside bounds", i)
+		}
+	}
+	d := &IncrementalDelaunay{
+		bounds:  bounds,
+		tr:      newGhostTriangulation(bounds.Center()),
+		removed: make(map[int]bool),
+	}
+	d.tr.touched = make(map[int]bool)
+	for _, p := range sites {
+		d.attach(len(d.sites), p)
+	}
+	return d, nil
+}
+
+// Insert adds a site and returns its id. The site must lie inside or on the bounds.
+func (d *IncrementalDelaunay) Insert(p Point) (int, error) {
+	if !d.bounds.ContainsPoint(p) {
+		return -1, fmt.Errorf("gaul IncrementalDelaunay.Insert: %v is not inside bounds", p)
+	}
+	id := len(d.sites)
+	d.attach(id, p)
+	return id, nil
+}
+
+// Remove deletes site id
+func (d *IncrementalDelaunay) Remove(id int) error {
+	if !d.live(id) {
+		return fmt.Errorf("gaul IncrementalDelaunay.Remove: no site %d", id)
+	}
+	d.detach(id)
+	d.vertex[id] = -1
+	d.removed[id] = true
+	return nil
+}
+
+// Move moves site id to p, which must lie inside or on the bounds
+func (d *IncrementalDelaunay) Move(id int, p Point) error {
+	if !d.live(id) {
+		return fmt.Errorf("gaul IncrementalDelaunay.Move: no site %d", id)
+	}
+	if !d.bounds.ContainsPoint(p) {
+		return fmt.Errorf("gaul IncrementalDelaunay.Move: %v is not inside bounds", p)
+	}
+	if p == d.sites[id] {
+		return nil
+	}
+	d.detach(id)
+	d.attach(id, p)
+	return nil
+}
+
+// Site returns site id, and false if there is no such site
+func (d *IncrementalDelaunay) Site(id int) (Point, bool) {
+	if !d.live(id) {
+		return Point{}, false
+	}
+	return d.sites[id], true
+}
+
+// Len returns the number of sites, duplicates included
+func (d *IncrementalDelaunay) Len() int {
+	n := 0
+	for _, v := range d.vertex {
+		if v >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Triangles returns the Delaunay triangles of the sites, each counterclockwise
+func (d *IncrementalDelaunay) Triangles() []Triangle {
+	return d.tr.triangles(nil)
+}
+
+// SiteTriangles returns the Delaunay triangles with a corner at site id,
+// counterclockwise around it
+func (d *IncrementalDelaunay) SiteTriangles(id int) []Triangle {
+	if !d.live(id) {
+		return nil
+	}
+	var out []Triangle
+	for _, t := range d.tr.around(d.vertex[id]) {
+		if v := d.tr.tris[t]; v[0] >= 3 && v[1] >= 3 && v[2] >= 3 {
+			out = append(out, d.tr.triangle(t))
+		}
+	}
+	return out
+}
+
+// Cell returns the Voronoi cell of site id clipped to the bounds, closed and
+// counterclockwise, or an empty curve if there is no such site
+func (d *IncrementalDelaunay) Cell(id int) Curve {
+	if !d.live(id) {
+		return Curve{}
+	}
+	pts, _ := d.cell(d.vertex[id])
+	return Curve{Points: pts, Closed: true}
+}
+
+// Neighbors returns the sites whose cells share an edge with the cell of site id
+// within the bounds, counterclockwise around it
+func (d *IncrementalDelaunay) Neighbors(id int) []int {
+	if !d.live(id) {
+		return nil
+	}
+	_, across := d.cell(d.vertex[id])
+	var out []int
+	seen := make(map[int]bool)
+	for _, v := range across {
+		if v >= 0 && !seen[v] {
+			seen[v] = true
+			out = append(out, d.ids[v][0])
+		}
+	}
+	return out
+}
+
+// Changed returns, in increasing order, the sites inserted, removed or moved since
+// the previous call and those whose triangles, cell or neighbours may have changed
+// with them. Sites not listed still have the same triangles, cell and neighbours.
+func (d *IncrementalDelaunay) Changed() []int {
+	set := d.removed
+	for v := range d.tr.touched {
+		if v < len(d.ids) {
+			for _, id := range d.ids[v] {
+				set[id] = true
+			}
+		}
+	}
+	out := make([]int, 0, len(set))
+	for id := range set {
+		out = append(out, id)
+	}
+	sort.Ints(out)
+	d.removed = make(map[int]bool)
+	d.tr.touched = make(map[int]bool)
+	return out
+}
+
+func (d *IncrementalDelaunay) live(id int) bool {
+	return id >= 0 && id < len(d.vertex) && d.vertex[id] >= 0
+}
+
+// attach puts site id at p, in the vertex already there if p is a duplicate
+func (d *IncrementalDelaunay) attach(id int, p Point) {
+	v := d.tr.insertPoint(p)
+	if id == len(d.sites) {
+		d.sites = append(d.sites, p)
+		d.vertex = append(d.vertex, v)
+	} else {
+		d.sites[id], d.vertex[id] = p, v
+	}
+	for len(d.ids) <= v {
+		d.ids = append(d.ids, nil)
+	}
+	ids := d.ids[v]
+	k := sort.SearchInts(ids, id)
+	ids = append(ids, 0)
+	copy(ids[k+1:], ids[k:])
+	ids[k] = id
+	d.ids[v] = ids
+	d.tr.touched[v] = true
+	if k == 0 && len(ids) > 1 {
+		d.touchAround(v)
+	}
+}
+
+// detach takes site id off its vertex, and removes the vertex if no other site is
+// there
+func (d *IncrementalDelaunay) detach(id int) {
+	v := d.vertex[id]
+	ids := d.ids[v]
+	k := 0
+	for ids[k] != id {
+		k++
+	}
+	d.ids[v] = append(ids[:k], ids[k+1:]...)
+	if len(d.ids[v]) == 0 {
+		d.ids[v] = nil
+		d.tr.removeVertex(v)
+		return
+	}
+	if k == 0 {
+		d.touchAround(v)
+	}
+}
+
+// touchAround marks the neighbours of vertex v as changed, as they name another site
+// for it now
+func (d *IncrementalDelaunay) touchAround(v int) {
+	for _, t := range d.tr.around(v) {
+		for _, w := range d.tr.tris[t] {
+			d.tr.touched[w] = true
+		}
+	}
+}
+
+// cell clips the bounds to the side of the bisector with each Delaunay neighbour
+// that holds vertex v. It returns the cell and, for each of its edges, the vertex
+// on the other side or -1 for the bounds.
+func (d *IncrementalDelaunay) cell(v int) ([]Point, []int) {
+	pts := d.bounds.ToCurve().Points
+	across := []int{-1, -1, -1, -1}
+	p := d.tr.pts[v]
+	for _, t := range d.tr.around(v) {
+		w := d.tr.tris[t][(d.tr.vertexIndex(t, v)+1)%3]
+		if w < 3 {
+			continue
+		}
+		pts, across = clipToBisector(pts, across, p, d.tr.pts[w], w)
+	}
+	// drop the edges that the clipping shrank to nothing
+	var outPts []Point
+	var outAcross []int
+	for k, q := range pts {
+		if voronoiPointEqual(q, pts[(k+1)%len(pts)]) {
+			continue
+		}
+		outPts = append(outPts, q)
+		outAcross = append(outAcross, across[k])
+	}
+	return outPts, outAcross
+}
+
+// clipToBisector keeps the part of the convex polygon pts that is nearer to p than
+// to q. across[k] labels the edge from pts[k] to the next point, and edges along the
+// bisector get the label w.
+func clipToBisector(pts []Point, across []int, p, q Point, w int) ([]Point, []int) {
+	n := Vec2FromPoints(p, q)
+	m := Point{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2}
+	side := func(x Point) float64 {
+		return (x.X-m.X)*n.X + (x.Y-m.Y)*n.Y
+	}
+	var outPts []Point
+	var outAcross []int
+	for k, a := range pts {
+		b := pts[(k+1)%len(pts)]
+		fa, fb := side(a), side(b)
+		if fa <= 0 {
+			label := across[k]
+			if fa == 0 && fb > 0 {
+				label = w
+			}
+			outPts = append(outPts, a)
+			outAcross = append(outAcross, label)
+		}
+		if (fa < 0 && fb > 0) || (fa > 0 && fb < 0) {
+			s := fa / (fa - fb)
+			label := across[k]
+			if fa < 0 {
+				label = w
+			}
+			outPts = append(outPts, Point{X: a.X + s*(b.X-a.X), Y: a.Y + s*(b.Y-a.Y)})
+			outAcross = append(outAcross, label)
+		}
+	}
+	return outPts, outAcross
+}
+
+// holeEdge is an edge a->b of the hole left by a removed vertex, with the hole on its
+// left and the triangle out on the other side
+type holeEdge struct {
+	a, b  int
+	out   int
+	fixed bool
+}
+
+// removeVertex deletes vertex v, which must not be on the outer boundary, and fills
+// the hole with the Delaunay triangles of the vertices around it: an ear of the hole
+// whose circumcircle holds none of those vertices is cut off until a triangle is left.
+func (tr *triangulation) removeVertex(v int) {
+	// around goes counterclockwise, so the edges opposite v already run in order
+	star := tr.around(v)
+	hole := make([]holeEdge, len(star))
+	ring := make([]int, len(star))
+	for i, t := range star {
+		k := (tr.vertexIndex(t, v) + 1) % 3
+		hole[i] = holeEdge{a: tr.tris[t][k], b: tr.tris[t][(k+1)%3], out: tr.adj[t][k], fixed: tr.fixed[t][k]}
+		ring[i] = hole[i].a
+	}
+	for _, t := range star {
+		tr.tris[t], tr.adj[t] = [3]int{-1, -1, -1}, [3]int{-1, -1, -1}
+	}
+	tr.free = append(tr.free, star...)
+	tr.freeVerts = append(tr.freeVerts, v)
+
+	for len(hole) > 3 {
+		n := len(hole)
+		ear, convex := -1, -1
+		for i := 0; i < n && ear < 0; i++ {
+			a, b, c := hole[i].a, hole[i].b, hole[(i+1)%n].b
+			if tr.orientVerts(a, b, c) <= 0 {
+				continue
+			}
+			if convex < 0 {
+				convex = i
+			}
+			empty := true
+			for _, w := range ring {
+				if w != a && w != b && w != c && tr.inCircle(a, b, c, w) {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				ear = i
+			}
+		}
+		if ear < 0 {
+			// not reached with exact predicates, but never loop forever
+			ear = convex
+		}
+		e1, e2 := hole[ear], hole[(ear+1)%n]
+		t := tr.newTri()
+		tr.setTri(t, e1.a, e1.b, e2.b)
+		tr.link(t, 0, e1.out, e1.fixed)
+		tr.link(t, 1, e2.out, e2.fixed)
+		hole[ear] = holeEdge{a: e1.a, b: e2.b, out: t}
+		j := (ear + 1) % n
+		hole = append(hole[:j], hole[j+1:]...)
+	}
+	t := tr.newTri()
+	tr.setTri(t, hole[0].a, hole[1].a, hole[2].a)
+	for k, e := range hole {
+		tr.link(t, k, e.out, e.fixed)
+	}
+	tr.last = t
+}
+
+// ghostDirs are the directions in which the super-triangle corners of a ghost
+// triangulation go off to infinity: counterclockwise around the origin, with small
+// integer coordinates so that predicates on them stay exact
+var ghostDirs = [3]Vec2{{X: -4, Y: -3}, {X: 4, Y: -3}, {X: 0, Y: 5}}
+
+// newGhostTriangulation returns an empty triangulation whose super-triangle corner k
+// lies at center + R*ghostDirs[k] for R going to infinity. The predicates take the
+// sign they have for all large enough R, so no circumcircle through sites reaches a
+// corner and the triangles among the sites are exactly their Delaunay triangles,
+// thin ones along the convex hull included.
+func newGhostTriangulation(center Point) *triangulation {
+	tr := &triangulation{pts: []Point{center, center, center}, vertTri: make([]int, 3), state: 88172645463325252, ghosts: true}
+	t := tr.newTri()
+	tr.setTri(t, 0, 1, 2)
+	return tr
+}
+
+// ghostPoint is the point p + R*d
+type ghostPoint struct {
+	p Point
+	d Vec2
+}
+
+func (tr *triangulation) ghost(v int) ghostPoint {
+	if tr.ghosts && v < 3 {
+		return ghostPoint{p: tr.pts[v], d: ghostDirs[v]}
+	}
+	return ghostPoint{p: tr.pts[v]}
+}
+
+// orientTo is [orient2] of vertices a and b and the point p
+func (tr *triangulation) orientTo(a, b int, p Point) float64 {
+	if !tr.ghosts || (a >= 3 && b >= 3) {
+		return orient2(tr.pts[a], tr.pts[b], p)
+	}
+	return ghostOrient(tr.ghost(a), tr.ghost(b), ghostPoint{p: p})
+}
+
+// orientVerts is [orient2] of vertices a, b and c
+func (tr *triangulation) orientVerts(a, b, c int) float64 {
+	if !tr.ghosts || (a >= 3 && b >= 3 && c >= 3) {
+		return orient2(tr.pts[a], tr.pts[b], tr.pts[c])
+	}
+	return ghostOrient(tr.ghost(a), tr.ghost(b), tr.ghost(c))
+}
+
+// inCircle is [inCircumcircle] of vertices a, b, c and d. With ghosts, ties are
+// broken as [delaunayInCircle] does, so that the triangles are those of
+// [DelaunayTriangles] even for cocircular sites.
+func (tr *triangulation) inCircle(a, b, c, d int) bool {
+	if !tr.ghosts {
+		return inCircumcircle(tr.pts[a], tr.pts[b], tr.pts[c], tr.pts[d])
+	}
+	if a >= 3 && b >= 3 && c >= 3 && d >= 3 {
+		return delaunayInCircle(tr.pts[a], tr.pts[b], tr.pts[c], tr.pts[d])
+	}
+	return ghostInCircle(tr.ghost(a), tr.ghost(b), tr.ghost(c), tr.ghost(d))
+}
+
+// ghostInCircle is [inCircumcircle] for large R
+func ghostInCircle(a, b, c, d ghostPoint) bool {
+	o := ghostOrient(a, b, c)
+	if o == 0 {
+		return false
+	}
+	det := ghostIncircle(a, b, c, d)
+	if o < 0 {
+		det = -det
+	}
+	return det > 0
+}
+
+// ghostPoly is a polynomial in R whose k-th coefficient is an expansion
+type ghostPoly [][]float64
+
+// ghostDiff returns the X (or Y) coordinate of a-b
+func ghostDiff(a, b ghostPoint, x bool) ghostPoly {
+	if x {
+		return ghostPoly{twoDiff(a.p.X, b.p.X), {a.d.X - b.d.X}}
+	}
+	return ghostPoly{twoDiff(a.p.Y, b.p.Y), {a.d.Y - b.d.Y}}
+}
+
+func (p ghostPoly) plus(q ghostPoly, sign float64) ghostPoly {
+	out := make(ghostPoly, max(len(p), len(q)))
+	copy(out, p)
+	for k, e := range q {
+		out[k] = expansionSum(out[k], scaleExpansion(e, sign))
+	}
+	return out
+}
+
+func (p ghostPoly) times(q ghostPoly) ghostPoly {
+	out := make(ghostPoly, len(p)+len(q)-1)
+	for i, e := range p {
+		for j, f := range q {
+			out[i+j] = expansionSum(out[i+j], expansionProduct(e, f))
+		}
+	}
+	return out
+}
+
+// sign returns the sign of p for large R, from its highest non-zero coefficient
+func (p ghostPoly) sign() float64 {
+	for k := len(p) - 1; k >= 0; k-- {
+		if len(p[k]) > 0 {
+			if v := expansionEstimate(p[k]); v != 0 {
+				return math.Copysign(1, v)
+			}
+		}
+	}
+	return 0
+}
+
+// ghostOrient is the sign of [orient2] for large R
+func ghostOrient(a, b, c ghostPoint) float64 {
+	bax, bay := ghostDiff(b, a, true), ghostDiff(b, a, false)
+	cax, cay := ghostDiff(c, a, true), ghostDiff(c, a, false)
+	return bax.times(cay).plus(bay.times(cax), -1).sign()
+}
+
+// ghostIncircle is the sign of [incircle] for large R
+func ghostIncircle(a, b, c, d ghostPoint) float64 {
+	adx, ady := ghostDiff(a, d, true), ghostDiff(a, d, false)
+	bdx, bdy := ghostDiff(b, d, true), ghostDiff(b, d, false)
+	cdx, cdy := ghostDiff(c, d, true), ghostDiff(c, d, false)
+	lift := func(x, y ghostPoly) ghostPoly {
+		return x.times(x).plus(y.times(y), 1)
+	}
+	cross := func(x1, y1, x2, y2 ghostPoly) ghostPoly {
+		return x1.times(y2).plus(x2.times(y1), -1)
+	}
+	det := lift(adx, ady).times(cross(bdx, bdy, cdx, cdy))
+	det = det.plus(lift(bdx, bdy).times(cross(cdx, cdy, adx, ady)), 1)
+	det = det.plus(lift(cdx, cdy).times(cross(adx, ady, bdx, bdy)), 1)
+	return det.sign()
+}
@@ -0,0 +1,250 @@
+package gaul
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triangleKeys identifies triangles by their corners, starting from the lowest
+func triangleKeys(tris []Triangle) map[[3]Point]bool {
+	out := make(map[[3]Point]bool, len(tris))
+	for _, t := range tris {
+		c := [3]Point{t.A, t.B, t.C}
+		for c[0].X > c[1].X || c[0].X > c[2].X || (c[0].X == c[1].X && c[0].Y > c[1].Y) || (c[0].X == c[2].X && c[0].Y > c[2].Y) {
+			c = [3]Point{c[1], c[2], c[0]}
+		}
+		out[c] = true
+	}
+	return out
+}
+
+// checkIncremental compares d with a full rebuild from the live sites
+func checkIncremental(t *testing.T, d *IncrementalDelaunay, bounds Rect, live map[int]Point) {
+	t.Helper()
+	var ids []int
+	for id := range live {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	sites := make([]Point, len(ids))
+	for i, id := range ids {
+		sites[i] = live[id]
+	}
+	require.Equal(t, len(ids), d.Len())
+	assert.Equal(t, triangleKeys(DelaunayTriangles(sites)), triangleKeys(d.Triangles()))
+
+	full, err := NewVoronoiDiagram(bounds, sites)
+	require.NoError(t, err)
+	for i, id := range ids {
+		p, ok := d.Site(id)
+		require.True(t, ok)
+		assert.Equal(t, sites[i], p)
+		c := d.Cell(id)
+		assert.InDelta(t, full.Cells[i].Area(), c.Area(), 1e-9, "site %d", id)
+		assert.Greater(t, c.SignedArea(), 0.0)
+		var want []int
+		for _, n := range full.Cells[i].Neighbors {
+			want = append(want, ids[n])
+		}
+		assert.ElementsMatch(t, want, d.Neighbors(id), "site %d", id)
+		for _, tri := range d.SiteTriangles(id) {
+			assert.True(t, tri.A == p || tri.B == p || tri.C == p)
+		}
+	}
+}
+
+func TestIncrementalDelaunay(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	bounds := Rect{X: -2, Y: 1, W: 6, H: 4}
+	random := func() Point {
+		return Point{X: bounds.X + bounds.W*rng.Float64(), Y: bounds.Y + bounds.H*rng.Float64()}
+	}
+	live := make(map[int]Point)
+	var sites []Point
+	for i := 0; i < 100; i++ {
+		sites = append(sites, random())
+		live[i] = sites[i]
+	}
+	d, err := NewIncrementalDelaunay(bounds, sites)
+	require.NoError(t, err)
+	checkIncremental(t, d, bounds, live)
+
+	for step := 0; step < 600; step++ {
+		var ids []int
+		for id := range live {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		switch r := rng.Intn(10); {
+		case r < 2 || len(ids) < 10:
+			p := random()
+			id, err := d.Insert(p)
+			require.NoError(t, err)
+			live[id] = p
+		case r < 4:
+			id := ids[rng.Intn(len(ids))]
+			require.NoError(t, d.Remove(id))
+			delete(live, id)
+		default:
+			// drift a little, as in an animation
+			id := ids[rng.Intn(len(ids))]
+			p := live[id]
+			p.X = Clamp(bounds.X, bounds.X+bounds.W, p.X+0.2*(rng.Float64()-0.5))
+			p.Y = Clamp(bounds.Y, bounds.Y+bounds.H, p.Y+0.2*(rng.Float64()-0.5))
+			require.NoError(t, d.Move(id, p))
+			live[id] = p
+		}
+		if step%50 == 0 {
+			checkIncremental(t, d, bounds, live)
+		}
+	}
+	checkIncremental(t, d, bounds, live)
+}
+
+func TestIncrementalDelaunay_Changed(t *testing.T) {
+	rng := rand.New(rand.NewSource(31))
+	bounds := Rect{W: 10, H: 10}
+	var sites []Point
+	for i := 0; i < 400; i++ {
+		sites = append(sites, Point{X: 10 * rng.Float64(), Y: 10 * rng.Float64()})
+	}
+	d, err := NewIncrementalDelaunay(bounds, sites)
+	require.NoError(t, err)
+	assert.Len(t, d.Changed(), len(sites))
+	assert.Empty(t, d.Changed())
+
+	before := make([]Curve, len(sites))
+	for i := range sites {
+		before[i] = d.Cell(i)
+	}
+	require.NoError(t, d.Move(7, Point{X: sites[7].X + 0.1, Y: sites[7].Y}))
+	require.NoError(t, d.Remove(11))
+	changed := d.Changed()
+	assert.Contains(t, changed, 7)
+	assert.Contains(t, changed, 11)
+	// a local change touches a few cells, and leaves the rest alone
+	assert.Less(t, len(changed), 40)
+	for i := range sites {
+		if i != 11 && !assert.ObjectsAreEqual(before[i], d.Cell(i)) {
+			assert.Contains(t, changed, i)
+		}
+	}
+}
+
+func TestIncrementalDelaunay_duplicates(t *testing.T) {
+	bounds := Rect{W: 4, H: 4}
+	d, err := NewIncrementalDelaunay(bounds, []Point{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 2, Y: 3}, {X: 1, Y: 1}})
+	require.NoError(t, err)
+	assert.Len(t, d.Triangles(), 1)
+	assert.Equal(t, d.Cell(0), d.Cell(3))
+	assert.Contains(t, d.Neighbors(1), 0)
+
+	require.NoError(t, d.Remove(0))
+	assert.Contains(t, d.Neighbors(1), 3)
+	assert.Len(t, d.Triangles(), 1)
+	require.NoError(t, d.Move(3, Point{X: 2, Y: 2}))
+	assert.Len(t, d.Triangles(), 1)
+	var sum float64
+	for _, id := range []int{1, 2, 3} {
+		c := d.Cell(id)
+		sum += c.Area()
+	}
+	assert.InDelta(t, 16, sum, 1e-9)
+
+	_, ok := d.Site(0)
+	assert.False(t, ok)
+	assert.Error(t, d.Remove(0))
+	assert.Error(t, d.Move(1, Point{X: 5, Y: 1}))
+	_, err = d.Insert(Point{X: -1, Y: 0})
+	assert.Error(t, err)
+	_, err = NewIncrementalDelaunay(Rect{W: 1}, nil)
+	assert.Error(t, err)
+	_, err = NewIncrementalDelaunay(bounds, []Point{{X: 5, Y: 5}})
+	assert.Error(t, err)
+}
+
+func TestIncrementalDelaunay_Triangles(t *testing.T) {
+	// with many sites some triangles along the convex hull are slivers, whose
+	// circumcircles reach far beyond the bounds
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		random := func() Point {
+			return Point{X: rng.Float64(), Y: rng.Float64()}
+		}
+		live := make(map[int]Point)
+		var sites []Point
+		for i := 0; i < 600; i++ {
+			sites = append(sites, random())
+			live[i] = sites[i]
+		}
+		d, err := NewIncrementalDelaunay(Rect{W: 1, H: 1}, sites)
+		require.NoError(t, err)
+		for step := 0; step < 90; step++ {
+			var ids []int
+			for id := range live {
+				ids = append(ids, id)
+			}
+			sort.Ints(ids)
+			switch {
+			case step%3 == 0 || len(ids) < 5:
+				p := random()
+				id, err := d.Insert(p)
+				require.NoError(t, err)
+				live[id] = p
+			case step%3 == 1:
+				id := ids[rng.Intn(len(ids))]
+				require.NoError(t, d.Remove(id))
+				delete(live, id)
+			default:
+				id, p := ids[rng.Intn(len(ids))], random()
+				require.NoError(t, d.Move(id, p))
+				live[id] = p
+			}
+		}
+		sites = sites[:0]
+		for _, p := range live {
+			sites = append(sites, p)
+		}
+		assert.Equal(t, triangleKeys(DelaunayTriangles(sites)), triangleKeys(d.Triangles()), "seed %d", seed)
+	}
+}
+
+func TestIncrementalDelaunay_grid(t *testing.T) {
+	// grid sites are cocircular four at a time, and split as DelaunayTriangles splits them
+	bounds := Rect{W: 10, H: 10}
+	var sites []Point
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			sites = append(sites, Point{X: float64(i) + 0.5, Y: float64(j) + 0.5})
+		}
+	}
+	d, err := NewIncrementalDelaunay(bounds, sites)
+	require.NoError(t, err)
+	live := make(map[int]Point)
+	for i, p := range sites {
+		live[i] = p
+	}
+	checkIncremental(t, d, bounds, live)
+
+	rng := rand.New(rand.NewSource(37))
+	for step := 0; step < 60; step++ {
+		id := rng.Intn(len(sites))
+		if _, ok := live[id]; !ok {
+			continue
+		}
+		if step%2 == 0 {
+			require.NoError(t, d.Remove(id))
+			delete(live, id)
+			continue
+		}
+		// onto a grid point, which may be taken
+		p := Point{X: float64(rng.Intn(10)) + 0.5, Y: float64(rng.Intn(10)) + 0.5}
+		require.NoError(t, d.Move(id, p))
+		live[id] = p
+	}
+	checkIncremental(t, d, bounds, live)
+}
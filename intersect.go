@@ -0,0 +1,345 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// IntersectionPoints returns the points shared by the segments l and k. The result is
+// nil when they don't meet, a single point when they cross or touch, and the two end
+// points of the shared sub-segment (ordered from l.P towards l.Q) when they overlap
+// collinearly.
+func (l Line) IntersectionPoints(k Line) []Point {
+	eps := segmentsTolerance([]taggedSegment{{Line: l}, {Line: k}})
+	pts, n := segmentIntersections(l.P, l.Q, k.P, k.Q, eps)
+	if n == 0 {
+		return nil
+	}
+	return append([]Point(nil), pts[:n]...)
+}
+
+// SegmentIntersection is an intersection between segments I and J (I < J) reported by
+// [LineIntersections]. Points holds the crossing or touching point, or the two end
+// points of the shared sub-segment when the segments overlap.
+type SegmentIntersection struct {
+	I, J   int
+	Points []Point
+}
+
+// CurveCrossing is an intersection between segment SegmentA of curve CurveA and
+// segment SegmentB of curve CurveB, reported by [CurveIntersections]. Segment i of a
+// curve runs from Points[i] to Points[i+1] (or back to Points[0] for the closing
+// segment of a closed curve). Points follows the convention of [SegmentIntersection].
+type CurveCrossing struct {
+	CurveA, SegmentA int
+	CurveB, SegmentB int
+	Points           []Point
+}
+
+// LineIntersections reports every pair of intersecting segments in lines, including
+// segments that touch at an end point and collinear overlaps. It uses a
+// Bentley–Ottmann sweep, running in O((n+k) log n) time for n segments and k
+// intersection points. Results are sorted by I, then J.
+func LineIntersections(lines []Line) []SegmentIntersection {
+	if len(lines) < 2 {
+		return nil
+	}
+	segs := make([]taggedSegment, len(lines))
+	for i, l := range lines {
+		segs[i] = taggedSegment{Line: l}
+	}
+	eps := segmentsTolerance(segs)
+	var out []SegmentIntersection
+	sweepIntersectingPairs(lines, eps, func(i, j int) {
+		pts, n := segmentIntersections(lines[i].P, lines[i].Q, lines[j].P, lines[j].Q, eps)
+		if n == 0 {
+			return
+		}
+		out = append(out, SegmentIntersection{I: i, J: j, Points: append([]Point(nil), pts[:n]...)})
+	})
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].I != out[b].I {
+			return out[a].I < out[b].I
+		}
+		return out[a].J < out[b].J
+	})
+	return out
+}
+
+// CurveIntersections reports every intersection among the segments of the given
+// curves, both between different curves and self-intersections within a curve.
+// Consecutive segments of the same curve that only meet at their shared vertex are
+// not reported.
+func CurveIntersections(curves []Curve) []CurveCrossing {
+	type segRef struct{ curve, seg, count int }
+	var lines []Line
+	var refs []segRef
+	for ci, c := range curves {
+		n := len(c.Points)
+		count := n - 1
+		if c.Closed && n > 2 {
+			count = n
+		}
+		for si := 0; si < count; si++ {
+			lines = append(lines, Line{P: c.Points[si], Q: c.Points[(si+1)%n]})
+			refs = append(refs, segRef{curve: ci, seg: si, count: count})
+		}
+	}
+	var out []CurveCrossing
+	for _, x := range LineIntersections(lines) {
+		a, b := refs[x.I], refs[x.J]
+		if a.curve == b.curve && len(x.Points) == 1 {
+			closed := curves[a.curve].Closed && a.count > 2
+			next := b.seg == a.seg+1 || (closed && a.seg == 0 && b.seg == a.count-1)
+			if next && sharedVertex(lines[x.I], lines[x.J], x.Points[0]) {
+				continue
+			}
+		}
+		out = append(out, CurveCrossing{
+			CurveA: a.curve, SegmentA: a.seg,
+			CurveB: b.curve, SegmentB: b.seg,
+			Points: x.Points,
+		})
+	}
+	return out
+}
+
+// sharedVertex reports whether p is an end point common to both segments
+func sharedVertex(l, k Line, p Point) bool {
+	onL := p.IsEqual(l.P) || p.IsEqual(l.Q)
+	onK := p.IsEqual(k.P) || p.IsEqual(k.Q)
+	return onL && onK
+}
+
+// sweepSegment is a segment in the Bentley–Ottmann status tree, stored left to right
+type sweepSegment struct {
+	node  *rbNode
+	index int
+	a, b  Point
+	slope float64
+}
+
+func (s *sweepSegment) bindToNode(node *rbNode) { s.node = node }
+func (s *sweepSegment) getNode() *rbNode        { return s.node }
+
+// yAt is the height of the segment on the sweep line through p. Vertical segments
+// report p.Y, since they are only in the status while they span the event point.
+func (s *sweepSegment) yAt(p Point) float64 {
+	switch {
+	case s.a.X == s.b.X:
+		return p.Y
+	case p.X == s.a.X:
+		return s.a.Y
+	case p.X == s.b.X:
+		return s.b.Y
+	}
+	return s.a.Y + (p.X-s.a.X)*s.slope
+}
+
+// sweepEvent is an event point, holding the segments whose left end point it is
+type sweepEvent struct {
+	node  *rbNode
+	p     Point
+	upper []*sweepSegment
+}
+
+func (e *sweepEvent) bindToNode(node *rbNode) { e.node = node }
+func (e *sweepEvent) getNode() *rbNode        { return e.node }
+
+// sweep holds the state of a Bentley–Ottmann sweep from left to right (ties broken
+// bottom to top, as if the sweep line were rotated slightly clockwise)
+type sweep struct {
+	eps    float64
+	events rbTree
+	status rbTree
+}
+
+// eventCompare orders points by x, then y, treating points within eps as equal
+func (sw *sweep) eventCompare(p, q Point) int {
+	if math.Abs(p.X-q.X) <= sw.eps && math.Abs(p.Y-q.Y) <= sw.eps {
+		return 0
+	}
+	if math.Abs(p.X-q.X) > sw.eps {
+		if p.X < q.X {
+			return -1
+		}
+		return 1
+	}
+	if p.Y < q.Y {
+		return -1
+	}
+	return 1
+}
+
+// addEvent returns the queued event at p, creating it if needed
+func (sw *sweep) addEvent(p Point) *sweepEvent {
+	var pred *rbNode
+	node := sw.events.root
+	for node != nil {
+		ev := node.value.(*sweepEvent)
+		c := sw.eventCompare(p, ev.p)
+		if c == 0 {
+			return ev
+		}
+		if c < 0 {
+			if node.left == nil {
+				pred = node.previous
+				break
+			}
+			node = node.left
+		} else {
+			if node.right == nil {
+				pred = node
+				break
+			}
+			node = node.right
+		}
+	}
+	ev := &sweepEvent{p: p}
+	sw.events.insertSuccessor(pred, ev)
+	return ev
+}
+
+// sweepIntersectingPairs calls fn once for each pair i < j of segments that the sweep
+// finds to share a point. The caller computes the actual intersection.
+func sweepIntersectingPairs(lines []Line, eps float64, fn func(i, j int)) {
+	sw := &sweep{eps: eps}
+	for i, l := range lines {
+		a, b := l.P, l.Q
+		if b.X < a.X || (b.X == a.X && b.Y < a.Y) {
+			a, b = b, a
+		}
+		s := &sweepSegment{index: i, a: a, b: b, slope: math.Inf(1)}
+		if a.X != b.X {
+			s.slope = (b.Y - a.Y) / (b.X - a.X)
+		}
+		ev := sw.addEvent(a)
+		ev.upper = append(ev.upper, s)
+		sw.addEvent(b)
+	}
+
+	reported := make(map[[2]int]struct{})
+	report := func(i, j int) {
+		if i == j {
+			return
+		}
+		if i > j {
+			i, j = j, i
+		}
+		if _, ok := reported[[2]int{i, j}]; ok {
+			return
+		}
+		reported[[2]int{i, j}] = struct{}{}
+		fn(i, j)
+	}
+
+	for sw.events.root != nil {
+		node := sw.events.getFirst(sw.events.root)
+		ev := node.value.(*sweepEvent)
+		sw.events.removeNode(node)
+		sw.handleEvent(ev, report)
+	}
+}
+
+func (sw *sweep) handleEvent(ev *sweepEvent, report func(i, j int)) {
+	p := ev.p
+	// segments in the status passing through p, whether they end here or continue
+	through := sw.segmentsThrough(p)
+	all := append(append([]*sweepSegment(nil), through...), ev.upper...)
+	for x := 0; x < len(all); x++ {
+		for y := x + 1; y < len(all); y++ {
+			report(all[x].index, all[y].index)
+		}
+	}
+
+	for _, s := range through {
+		sw.status.removeNode(s.node)
+	}
+	// upper and continuing segments, ordered just to the right of p; zero length
+	// segments are reported but never enter the status
+	var inserted []*sweepSegment
+	for _, s := range all {
+		if sw.eventCompare(s.b, p) > 0 {
+			inserted = append(inserted, s)
+		}
+	}
+	sort.SliceStable(inserted, func(x, y int) bool { return inserted[x].slope < inserted[y].slope })
+
+	pred := sw.statusPredecessor(p)
+	if len(inserted) == 0 {
+		var succ *rbNode
+		if pred != nil {
+			succ = pred.next
+		} else if sw.status.root != nil {
+			succ = sw.status.getFirst(sw.status.root)
+		}
+		if pred != nil && succ != nil {
+			sw.checkPair(pred.value.(*sweepSegment), succ.value.(*sweepSegment), p)
+		}
+		return
+	}
+	after := pred
+	for _, s := range inserted {
+		sw.status.insertSuccessor(after, s)
+		after = s.node
+	}
+	lowest := inserted[0].node
+	highest := inserted[len(inserted)-1].node
+	if lowest.previous != nil {
+		sw.checkPair(lowest.previous.value.(*sweepSegment), inserted[0], p)
+	}
+	if highest.next != nil {
+		sw.checkPair(inserted[len(inserted)-1], highest.next.value.(*sweepSegment), p)
+	}
+}
+
+// segmentsThrough returns the status segments whose height at p is within eps of p.Y
+func (sw *sweep) segmentsThrough(p Point) []*sweepSegment {
+	var first *rbNode
+	node := sw.status.root
+	for node != nil {
+		s := node.value.(*sweepSegment)
+		if s.yAt(p) >= p.Y-sw.eps {
+			first = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	var out []*sweepSegment
+	for n := first; n != nil; n = n.next {
+		s := n.value.(*sweepSegment)
+		if s.yAt(p) > p.Y+sw.eps {
+			break
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// statusPredecessor returns the highest status node strictly below p
+func (sw *sweep) statusPredecessor(p Point) *rbNode {
+	var pred *rbNode
+	node := sw.status.root
+	for node != nil {
+		if node.value.(*sweepSegment).yAt(p) < p.Y {
+			pred = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+	return pred
+}
+
+// checkPair queues the crossing of two neighbouring segments if it lies after p
+func (sw *sweep) checkPair(s, t *sweepSegment, p Point) {
+	pts, n := segmentIntersections(s.a, s.b, t.a, t.b, sw.eps)
+	if n != 1 {
+		// no crossing, or a collinear overlap whose end points are events already
+		return
+	}
+	if sw.eventCompare(pts[0], p) > 0 {
+		sw.addEvent(pts[0])
+	}
+}
@@ -0,0 +1,120 @@
+package gaul
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLine_IntersectionPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		l, k Line
+		want []Point
+	}{
+		{"crossing", Line{P: Point{0, 0}, Q: Point{2, 2}}, Line{P: Point{0, 2}, Q: Point{2, 0}}, []Point{{1, 1}}},
+		{"disjoint", Line{P: Point{0, 0}, Q: Point{1, 0}}, Line{P: Point{0, 1}, Q: Point{1, 1}}, nil},
+		{"touching", Line{P: Point{0, 0}, Q: Point{1, 0}}, Line{P: Point{1, 0}, Q: Point{1, 1}}, []Point{{1, 0}}},
+		{"t-junction", Line{P: Point{0, 0}, Q: Point{2, 0}}, Line{P: Point{1, 0}, Q: Point{1, 1}}, []Point{{1, 0}}},
+		{"overlap", Line{P: Point{3, 0}, Q: Point{0, 0}}, Line{P: Point{1, 0}, Q: Point{5, 0}}, []Point{{3, 0}, {1, 0}}},
+		{"collinear apart", Line{P: Point{0, 0}, Q: Point{1, 1}}, Line{P: Point{2, 2}, Q: Point{3, 3}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.l.IntersectionPoints(tt.k)
+			require.Len(t, got, len(tt.want))
+			for i := range got {
+				assert.InDelta(t, tt.want[i].X, got[i].X, 1e-12)
+				assert.InDelta(t, tt.want[i].Y, got[i].Y, 1e-12)
+			}
+		})
+	}
+}
+
+func bruteForceIntersections(lines []Line) [][2]int {
+	var out [][2]int
+	for i := range lines {
+		for j := i + 1; j < len(lines); j++ {
+			if len(lines[i].IntersectionPoints(lines[j])) > 0 {
+				out = append(out, [2]int{i, j})
+			}
+		}
+	}
+	return out
+}
+
+func intersectionPairs(xs []SegmentIntersection) [][2]int {
+	var out [][2]int
+	for _, x := range xs {
+		out = append(out, [2]int{x.I, x.J})
+	}
+	return out
+}
+
+func TestLineIntersections_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for trial := 0; trial < 30; trial++ {
+		n := 5 + rng.Intn(60)
+		lines := make([]Line, n)
+		for i := range lines {
+			p := Point{X: rng.Float64(), Y: rng.Float64()}
+			q := Point{X: p.X + 0.4*(rng.Float64()-0.5), Y: p.Y + 0.4*(rng.Float64()-0.5)}
+			lines[i] = Line{P: p, Q: q}
+		}
+		assert.Equal(t, bruteForceIntersections(lines), intersectionPairs(LineIntersections(lines)))
+	}
+}
+
+func TestLineIntersections_degenerate(t *testing.T) {
+	// a grid sharing many end points, with vertical lines, overlaps and repeated
+	// crossings at a single point
+	var lines []Line
+	for i := 0; i <= 4; i++ {
+		f := float64(i)
+		lines = append(lines,
+			Line{P: Point{0, f}, Q: Point{4, f}},
+			Line{P: Point{f, 0}, Q: Point{f, 4}},
+			Line{P: Point{f, f}, Q: Point{f + 1, f}},
+		)
+	}
+	lines = append(lines,
+		Line{P: Point{0, 0}, Q: Point{4, 4}},
+		Line{P: Point{0, 4}, Q: Point{4, 0}},
+		Line{P: Point{1, 1}, Q: Point{3, 3}},
+		Line{P: Point{2, 2}, Q: Point{2, 2}},
+	)
+	assert.Equal(t, bruteForceIntersections(lines), intersectionPairs(LineIntersections(lines)))
+}
+
+func TestLineIntersections_rounded(t *testing.T) {
+	// end points on a coarse lattice produce many touching and collinear cases
+	rng := rand.New(rand.NewSource(9))
+	for trial := 0; trial < 30; trial++ {
+		lines := make([]Line, 40)
+		for i := range lines {
+			lines[i] = Line{
+				P: Point{X: float64(rng.Intn(6)), Y: float64(rng.Intn(6))},
+				Q: Point{X: float64(rng.Intn(6)), Y: float64(rng.Intn(6))},
+			}
+		}
+		assert.Equal(t, bruteForceIntersections(lines), intersectionPairs(LineIntersections(lines)))
+	}
+}
+
+func TestCurveIntersections(t *testing.T) {
+	bowtie := Curve{Closed: true, Points: []Point{{0, 0}, {2, 2}, {2, 0}, {0, 2}}}
+	got := CurveIntersections([]Curve{bowtie})
+	require.Len(t, got, 1, "only the crossing, not the shared vertices")
+	assert.Equal(t, CurveCrossing{CurveA: 0, SegmentA: 0, CurveB: 0, SegmentB: 2, Points: []Point{{1, 1}}}, got[0])
+
+	got = CurveIntersections([]Curve{square(0, 0, 2), square(1, 1, 2)})
+	require.Len(t, got, 2)
+	for _, x := range got {
+		assert.Equal(t, 0, x.CurveA)
+		assert.Equal(t, 1, x.CurveB)
+	}
+
+	assert.Empty(t, CurveIntersections([]Curve{{Points: []Point{{0, 0}, {1, 0}, {1, 1}}}}))
+}
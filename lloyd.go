@@ -0,0 +1,130 @@
+package gaul
+
+import (
+	"fmt"
+	"math"
+)
+
+// LloydOptions controls [LloydWithRect] and [LloydWithCurve]
+type LloydOptions struct {
+	// Iterations is the most times the sites are moved; it must be positive
+	Iterations int
+	// Tolerance stops the relaxation early once no site moves farther than it. Zero
+	// runs all iterations.
+	Tolerance float64
+	// Density weights the centroids, so that cells shrink where it is high. It should
+	// be non-negative; nil is uniform.
+	Density func(Point) float64
+	// Subdivisions is how finely each cell is sampled for Density: the cell is fanned
+	// into triangles and each of those is cut into Subdivisions² smaller ones, with
+	// the density taken at their centroids. Zero means 4.
+	Subdivisions int
+}
+
+const defaultLloydSubdivisions = 4
+
+// LloydWithRect relaxes sites within bounds by Lloyd's algorithm: each iteration
+// computes the Voronoi diagram of the sites with [VoronoiWithRect] and moves every
+// site to the centroid of its cell. The sites spread out evenly, converging towards a
+// centroidal Voronoi tessellation, or one weighted by opts.Density. The result has the
+// same length as sites; the input is not modified. Duplicate sites move together.
+func LloydWithRect(bounds Rect, sites []Point, opts LloydOptions) ([]Point, error) {
+	return lloyd("LloydWithRect", sites, opts, func(pts []Point) ([]Curve, error) {
+		return VoronoiWithRect(bounds, pts)
+	}, bounds.ContainsPoint)
+}
+
+// LloydWithCurve is [LloydWithRect] within a convex boundary, using
+// [VoronoiWithCurve].
+func LloydWithCurve(boundary Curve, sites []Point, opts LloydOptions) ([]Point, error) {
+	poly := ensurePolygonCCW(append([]Point(nil), boundary.Points...))
+	return lloyd("LloydWithCurve", sites, opts, func(pts []Point) ([]Curve, error) {
+		return VoronoiWithCurve(boundary, pts)
+	}, func(p Point) bool {
+		return voronoiPointInOrOnConvexCCW(p, poly)
+	})
+}
+
+func lloyd(name string, sites []Point, opts LloydOptions, cells func([]Point) ([]Curve, error), inside func(Point) bool) ([]Point, error) {
+	if opts.Iterations <= 0 {
+		return nil, fmt.Errorf("gaul %s: iterations must be positive", name)
+	}
+	n := opts.Subdivisions
+	if n <= 0 {
+		n = defaultLloydSubdivisions
+	}
+	pts := append([]Point(nil), sites...)
+	for it := 0; it < opts.Iterations; it++ {
+		curves, err := cells(pts)
+		if err != nil {
+			return nil, err
+		}
+		moved := 0.0
+		for i, c := range curves {
+			p, ok := cellCentroid(c, opts.Density, n)
+			// a centroid rounded just outside the boundary would be rejected by
+			// the next diagram
+			if !ok || !inside(p) {
+				continue
+			}
+			moved = math.Max(moved, Distance(p, pts[i]))
+			pts[i] = p
+		}
+		if moved <= opts.Tolerance {
+			break
+		}
+	}
+	return pts, nil
+}
+
+// cellCentroid returns the centroid of a convex cell weighted by density, and false
+// if the cell has no area or no mass
+func cellCentroid(c Curve, density func(Point) float64, n int) (Point, bool) {
+	if len(c.Points) < 3 || c.Area() <= 0 {
+		return Point{}, false
+	}
+	if density == nil {
+		return c.Centroid(), true
+	}
+	var mass, mx, my float64
+	a := c.Points[0]
+	for i := 1; i+1 < len(c.Points); i++ {
+		m, centroid := integrateTriangle(Triangle{A: a, B: c.Points[i], C: c.Points[i+1]}, density, n)
+		mass += m
+		mx += m * centroid.X
+		my += m * centroid.Y
+	}
+	if mass <= 0 {
+		return Point{}, false
+	}
+	return Point{X: mx / mass, Y: my / mass}, true
+}
+
+// integrateTriangle returns the mass of density over t and its centre of mass,
+// cutting t into n² similar triangles and sampling each at its centroid
+func integrateTriangle(t Triangle, density func(Point) float64, n int) (float64, Point) {
+	u, v := Vec2FromPoints(t.A, t.B), Vec2FromPoints(t.A, t.C)
+	area := math.Abs(u.X*v.Y-u.Y*v.X) / 2 / float64(n*n)
+	at := func(s, r float64) Point {
+		return Point{X: t.A.X + (s*u.X+r*v.X)/float64(n), Y: t.A.Y + (s*u.Y+r*v.Y)/float64(n)}
+	}
+	var mass, mx, my float64
+	sample := func(p Point) {
+		w := density(p) * area
+		mass += w
+		mx += w * p.X
+		my += w * p.Y
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; i+j < n; j++ {
+			sample(at(float64(i)+1.0/3, float64(j)+1.0/3))
+			if i+j < n-1 {
+				sample(at(float64(i)+2.0/3, float64(j)+2.0/3))
+			}
+		}
+	}
+	if mass <= 0 {
+		return 0, t.Centroid()
+	}
+	return mass, Point{X: mx / mass, Y: my / mass}
+}
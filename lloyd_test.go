@@ -0,0 +1,124 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func minSiteDistance(pts []Point) float64 {
+	d := math.Inf(1)
+	for i := range pts {
+		for j := range pts[:i] {
+			d = math.Min(d, Distance(pts[i], pts[j]))
+		}
+	}
+	return d
+}
+
+func TestLloydWithRect(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	bounds := Rect{X: 0, Y: 0, W: 4, H: 3}
+	var sites []Point
+	for i := 0; i < 60; i++ {
+		sites = append(sites, Point{X: 4 * rng.Float64(), Y: 3 * rng.Float64()})
+	}
+	before := append([]Point(nil), sites...)
+	out, err := LloydWithRect(bounds, sites, LloydOptions{Iterations: 500, Tolerance: 1e-3})
+	require.NoError(t, err)
+	require.Len(t, out, len(sites))
+	assert.Equal(t, before, sites)
+	assert.Greater(t, minSiteDistance(out), 2*minSiteDistance(sites))
+
+	// stopped near convergence: every site is close to the centroid of its cell
+	cells, err := VoronoiWithRect(bounds, out)
+	require.NoError(t, err)
+	for i, c := range cells {
+		assert.Less(t, Distance(out[i], c.Centroid()), 2e-3)
+	}
+
+	// relaxing lowers the energy, the second moment of each cell about its site,
+	// found by fanning the cell from the site
+	energy := func(pts []Point) float64 {
+		cells, err := VoronoiWithRect(bounds, pts)
+		require.NoError(t, err)
+		var e float64
+		for i, c := range cells {
+			for k := range c.Points {
+				b := Vec2FromPoints(pts[i], c.Points[k])
+				d := Vec2FromPoints(pts[i], c.Points[(k+1)%len(c.Points)])
+				area := (b.X*d.Y - b.Y*d.X) / 2
+				e += area / 6 * (b.Dot(b) + d.Dot(d) + b.Dot(d))
+			}
+		}
+		return e
+	}
+	prev := energy(sites)
+	for _, it := range []int{1, 5, 25} {
+		pts, err := LloydWithRect(bounds, sites, LloydOptions{Iterations: it})
+		require.NoError(t, err)
+		e := energy(pts)
+		assert.Less(t, e, prev)
+		prev = e
+	}
+	assert.Less(t, energy(out), prev)
+
+	// one iteration moves every site to its centroid
+	cells, err = VoronoiWithRect(bounds, sites)
+	require.NoError(t, err)
+	once, err := LloydWithRect(bounds, sites, LloydOptions{Iterations: 1})
+	require.NoError(t, err)
+	for i, c := range cells {
+		assert.Equal(t, c.Centroid(), once[i])
+	}
+
+	_, err = LloydWithRect(bounds, sites, LloydOptions{})
+	assert.Error(t, err)
+	_, err = LloydWithRect(bounds, []Point{{X: 5, Y: 0}}, LloydOptions{Iterations: 1})
+	assert.Error(t, err)
+}
+
+func TestLloydWithCurve_density(t *testing.T) {
+	boundary := Curve{Closed: true, Points: []Point{{0, 0}, {2, 0}, {2, 1}, {0, 1}}}
+	rng := rand.New(rand.NewSource(31))
+	var sites []Point
+	for i := 0; i < 80; i++ {
+		sites = append(sites, Point{X: 2 * rng.Float64(), Y: rng.Float64()})
+	}
+	density := func(p Point) float64 { return math.Exp(2 * p.X) }
+	out, err := LloydWithCurve(boundary, sites, LloydOptions{Iterations: 200, Density: density})
+	require.NoError(t, err)
+	right := 0
+	for _, p := range out {
+		require.True(t, boundary.ContainsPoint(p, NonZero) || boundary.PointOnEdge(p, 1e-9))
+		if p.X > 1 {
+			right++
+		}
+	}
+	// cell areas go as density^-1/2 in the limit, which puts 73% of the sites on the
+	// right; Lloyd's algorithm gets there slowly
+	assert.Greater(t, right, 50)
+
+	uniform, err := LloydWithCurve(boundary, sites, LloydOptions{Iterations: 60, Density: func(Point) float64 { return 1 }})
+	require.NoError(t, err)
+	plain, err := LloydWithCurve(boundary, sites, LloydOptions{Iterations: 60})
+	require.NoError(t, err)
+	for i := range plain {
+		assert.InDelta(t, 0, Distance(plain[i], uniform[i]), 1e-9)
+	}
+}
+
+func TestIntegrateTriangle(t *testing.T) {
+	tri := Triangle{A: Point{X: 1, Y: 0}, B: Point{X: 4, Y: 1}, C: Point{X: 2, Y: 3}}
+	mass, c := integrateTriangle(tri, func(Point) float64 { return 2 }, 5)
+	assert.InDelta(t, 2*tri.Area(), mass, 1e-12)
+	assert.InDelta(t, tri.Centroid().X, c.X, 1e-12)
+	assert.InDelta(t, tri.Centroid().Y, c.Y, 1e-12)
+	// sampling at centroids integrates linear functions exactly
+	mass, _ = integrateTriangle(tri, func(p Point) float64 { return p.X + 2*p.Y }, 3)
+	g := tri.Centroid()
+	assert.InDelta(t, (g.X+2*g.Y)*tri.Area(), mass, 1e-12)
+}
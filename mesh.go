@@ -0,0 +1,261 @@
+package gaul
+
+// Mesh is a triangle mesh with half-edge adjacency. Triangle t owns the half-edges
+// 3t, 3t+1 and 3t+2, half-edge 3t+k running from Triangles[t][k] to
+// Triangles[t][(k+1)%3], so a half-edge index gives its triangle and its neighbours in
+// the triangle without any lookup. Each half-edge is paired with its twin, the
+// half-edge in the opposite direction in the triangle across the edge, or -1 on the
+// boundary of the mesh.
+type Mesh struct {
+	Vertices []Point
+	// Triangles holds the vertex indices of each triangle, counterclockwise
+	Triangles  [][3]int
+	twins      []int
+	vertexEdge []int // a half-edge leaving each vertex, on the boundary if it has one
+}
+
+// NewMesh builds a mesh from triangles, joining corners at equal points into shared
+// vertices. Clockwise triangles are reversed and degenerate ones dropped. Edges that
+// more than two triangles share, or that two triangles run in the same direction, are
+// left on the boundary.
+func NewMesh(tris []Triangle) *Mesh {
+	m := &Mesh{}
+	index := make(map[Point]int)
+	vertex := func(p Point) int {
+		if i, ok := index[p]; ok {
+			return i
+		}
+		index[p] = len(m.Vertices)
+		m.Vertices = append(m.Vertices, p)
+		return len(m.Vertices) - 1
+	}
+	for _, t := range tris {
+		o := orient2(t.A, t.B, t.C)
+		if o == 0 {
+			continue
+		}
+		if o < 0 {
+			t = t.Reverse()
+		}
+		m.Triangles = append(m.Triangles, [3]int{vertex(t.A), vertex(t.B), vertex(t.C)})
+	}
+	m.twins = make([]int, 3*len(m.Triangles))
+	halfEdges := make(map[[2]int]int, len(m.twins))
+	for h := range m.twins {
+		m.twins[h] = -1
+		key := [2]int{m.Origin(h), m.Target(h)}
+		if _, ok := halfEdges[key]; ok {
+			halfEdges[key] = -1
+		} else {
+			halfEdges[key] = h
+		}
+	}
+	for h := range m.twins {
+		g, ok := halfEdges[[2]int{m.Target(h), m.Origin(h)}]
+		if ok && g >= 0 && halfEdges[[2]int{m.Origin(h), m.Target(h)}] == h {
+			m.twins[h] = g
+		}
+	}
+	m.vertexEdge = make([]int, len(m.Vertices))
+	for v := range m.vertexEdge {
+		m.vertexEdge[v] = -1
+	}
+	for h := range m.twins {
+		if v := m.Origin(h); m.vertexEdge[v] < 0 || m.twins[h] < 0 {
+			m.vertexEdge[v] = h
+		}
+	}
+	return m
+}
+
+// DelaunayMesh returns the Delaunay triangulation of the sites as a mesh, see
+// [DelaunayTriangles]
+func DelaunayMesh(sites []Point) *Mesh {
+	return NewMesh(DelaunayTriangles(sites))
+}
+
+// PolygonMesh returns the triangulation of a region as a mesh, see
+// [TriangulatePolygon]
+func PolygonMesh(region []Curve, rule FillRule) *Mesh {
+	return NewMesh(TriangulatePolygon(region, rule))
+}
+
+// Face returns the triangle of half-edge h
+func (m *Mesh) Face(h int) int {
+	return h / 3
+}
+
+// Next returns the half-edge after h in its triangle
+func (m *Mesh) Next(h int) int {
+	if h%3 == 2 {
+		return h - 2
+	}
+	return h + 1
+}
+
+// Prev returns the half-edge before h in its triangle
+func (m *Mesh) Prev(h int) int {
+	if h%3 == 0 {
+		return h + 2
+	}
+	return h - 1
+}
+
+// Twin returns the half-edge opposite h, or -1 if h is on the boundary
+func (m *Mesh) Twin(h int) int {
+	return m.twins[h]
+}
+
+// Origin returns the vertex half-edge h starts from
+func (m *Mesh) Origin(h int) int {
+	return m.Triangles[h/3][h%3]
+}
+
+// Target returns the vertex half-edge h points to
+func (m *Mesh) Target(h int) int {
+	return m.Triangles[h/3][(h+1)%3]
+}
+
+// Edge returns half-edge h as a line
+func (m *Mesh) Edge(h int) Line {
+	return Line{P: m.Vertices[m.Origin(h)], Q: m.Vertices[m.Target(h)]}
+}
+
+// Triangle returns triangle t
+func (m *Mesh) Triangle(t int) Triangle {
+	v := m.Triangles[t]
+	return Triangle{A: m.Vertices[v[0]], B: m.Vertices[v[1]], C: m.Vertices[v[2]]}
+}
+
+// Neighbors returns the triangles sharing an edge with triangle t
+func (m *Mesh) Neighbors(t int) []int {
+	var out []int
+	for h := 3 * t; h < 3*t+3; h++ {
+		if g := m.twins[h]; g >= 0 {
+			out = append(out, m.Face(g))
+		}
+	}
+	return out
+}
+
+// outgoing returns the half-edges leaving vertex v, counterclockwise, starting on the
+// boundary if v is on it. Where separate fans of triangles meet at v only one of them
+// is visited.
+func (m *Mesh) outgoing(v int) []int {
+	start := m.vertexEdge[v]
+	if start < 0 {
+		return nil
+	}
+	out := []int{start}
+	for h := m.twins[m.Prev(start)]; h >= 0 && h != start; h = m.twins[m.Prev(h)] {
+		out = append(out, h)
+	}
+	return out
+}
+
+// VertexRing returns the vertices joined to vertex v by an edge, counterclockwise
+// around it
+func (m *Mesh) VertexRing(v int) []int {
+	hs := m.outgoing(v)
+	var out []int
+	for _, h := range hs {
+		out = append(out, m.Target(h))
+	}
+	if len(hs) > 0 {
+		if last := m.Prev(hs[len(hs)-1]); m.twins[last] < 0 {
+			out = append(out, m.Origin(last))
+		}
+	}
+	return out
+}
+
+// VertexTriangles returns the triangles around vertex v, counterclockwise
+func (m *Mesh) VertexTriangles(v int) []int {
+	var out []int
+	for _, h := range m.outgoing(v) {
+		out = append(out, m.Face(h))
+	}
+	return out
+}
+
+// IsBoundaryVertex reports whether vertex v lies on the boundary of the mesh
+func (m *Mesh) IsBoundaryVertex(v int) bool {
+	h := m.vertexEdge[v]
+	return h < 0 || m.twins[h] < 0
+}
+
+// BoundaryEdges returns the half-edges without a twin. With the mesh on their left
+// they run counterclockwise around its outer boundaries and clockwise around holes.
+func (m *Mesh) BoundaryEdges() []int {
+	var out []int
+	for h, g := range m.twins {
+		if g < 0 {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// Flip replaces the edge of half-edge h, the diagonal of the quadrilateral formed by
+// the two triangles sharing it, by the other diagonal. It reports false, and leaves
+// the mesh unchanged, if h is on the boundary or the quadrilateral isn't strictly
+// convex. The two triangles keep their indices.
+func (m *Mesh) Flip(h int) bool {
+	g := m.twins[h]
+	if g < 0 {
+		return false
+	}
+	t, u := m.Face(h), m.Face(g)
+	a, b, c := m.Origin(h), m.Target(h), m.Origin(m.Prev(h))
+	d := m.Origin(m.Prev(g))
+	pa, pb, pc, pd := m.Vertices[a], m.Vertices[b], m.Vertices[c], m.Vertices[d]
+	if orient2(pc, pa, pd) <= 0 || orient2(pd, pb, pc) <= 0 {
+		return false
+	}
+	tBC, tCA := m.twins[m.Next(h)], m.twins[m.Prev(h)]
+	uAD, uDB := m.twins[m.Next(g)], m.twins[m.Prev(g)]
+	m.Triangles[t] = [3]int{c, a, d}
+	m.Triangles[u] = [3]int{d, b, c}
+	link := func(h, g int) {
+		m.twins[h] = g
+		if g >= 0 {
+			m.twins[g] = h
+		}
+	}
+	link(3*t, tCA)
+	link(3*t+1, uAD)
+	link(3*u, uDB)
+	link(3*u+1, tBC)
+	link(3*t+2, 3*u+2)
+	for _, v := range []int{a, b, c, d} {
+		if e := m.vertexEdge[v]; m.Face(e) == t || m.Face(e) == u {
+			m.vertexEdge[v] = -1
+		}
+	}
+	for _, e := range []int{3 * t, 3*t + 1, 3*t + 2, 3 * u, 3*u + 1, 3*u + 2} {
+		if v := m.Origin(e); m.vertexEdge[v] < 0 || m.twins[e] < 0 {
+			m.vertexEdge[v] = e
+		}
+	}
+	return true
+}
+
+// ToTriangles returns the triangles of the mesh
+func (m *Mesh) ToTriangles() []Triangle {
+	out := make([]Triangle, len(m.Triangles))
+	for t := range m.Triangles {
+		out[t] = m.Triangle(t)
+	}
+	return out
+}
+
+// ToLines returns the edges of the mesh, each shared edge once
+func (m *Mesh) ToLines() []Line {
+	var out []Line
+	for h, g := range m.twins {
+		if g < h {
+			out = append(out, m.Edge(h))
+		}
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package gaul
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertMeshConsistent checks the half-edge invariants of a mesh
+func assertMeshConsistent(t *testing.T, m *Mesh) {
+	t.Helper()
+	for h := 0; h < 3*len(m.Triangles); h++ {
+		assert.Equal(t, h, m.Next(m.Prev(h)))
+		assert.Equal(t, m.Target(h), m.Origin(m.Next(h)))
+		if g := m.Twin(h); g >= 0 {
+			assert.Equal(t, h, m.Twin(g))
+			assert.Equal(t, m.Origin(h), m.Target(g))
+			assert.Equal(t, m.Target(h), m.Origin(g))
+		}
+	}
+	for i := range m.Triangles {
+		tri := m.Triangle(i)
+		assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+	}
+}
+
+func TestMesh_delaunay(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	var pts []Point
+	for i := 0; i < 200; i++ {
+		pts = append(pts, Point{X: rng.Float64() * 10, Y: rng.Float64() * 10})
+	}
+	m := DelaunayMesh(pts)
+	require.NotEmpty(t, m.Triangles)
+	assertMeshConsistent(t, m)
+
+	// a triangulated disc has V - E + F = 1
+	lines := m.ToLines()
+	assert.Equal(t, 1, len(m.Vertices)-len(lines)+len(m.Triangles))
+	assert.Len(t, lines, len(triangleEdgeSet(m.ToTriangles())))
+
+	// the boundary encloses the triangles
+	boundary := m.BoundaryEdges()
+	require.NotEmpty(t, boundary)
+	var area float64
+	for _, h := range boundary {
+		assert.True(t, m.IsBoundaryVertex(m.Origin(h)))
+		l := m.Edge(h)
+		area += (l.P.X*l.Q.Y - l.Q.X*l.P.Y) / 2
+	}
+	assert.InDelta(t, trianglesArea(m.ToTriangles()), area, 1e-9)
+
+	for i := range m.Triangles {
+		for _, n := range m.Neighbors(i) {
+			shared := 0
+			for _, v := range m.Triangles[i] {
+				for _, w := range m.Triangles[n] {
+					if v == w {
+						shared++
+					}
+				}
+			}
+			assert.Equal(t, 2, shared)
+		}
+	}
+}
+
+func TestMesh_vertexRing(t *testing.T) {
+	var tris []Triangle
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			a := Point{X: float64(i), Y: float64(j)}
+			b := Point{X: float64(i + 1), Y: float64(j)}
+			c := Point{X: float64(i + 1), Y: float64(j + 1)}
+			d := Point{X: float64(i), Y: float64(j + 1)}
+			tris = append(tris, Triangle{A: a, B: b, C: c}, Triangle{A: a, B: c, C: d})
+		}
+	}
+	m := NewMesh(tris)
+	assertMeshConsistent(t, m)
+	require.Len(t, m.Vertices, 16)
+	assert.Len(t, m.BoundaryEdges(), 12)
+
+	vertex := func(x, y float64) int {
+		for i, p := range m.Vertices {
+			if p.X == x && p.Y == y {
+				return i
+			}
+		}
+		return -1
+	}
+	center := vertex(1, 1)
+	assert.False(t, m.IsBoundaryVertex(center))
+	ring := m.VertexRing(center)
+	assert.Len(t, ring, 6)
+	assert.Len(t, m.VertexTriangles(center), 6)
+	// counterclockwise: each turn around the vertex is to the left
+	for i := range ring {
+		p, q := m.Vertices[ring[i]], m.Vertices[ring[(i+1)%len(ring)]]
+		assert.Greater(t, orient2(m.Vertices[center], p, q), 0.0)
+	}
+
+	edge := vertex(2, 0)
+	assert.True(t, m.IsBoundaryVertex(edge))
+	ring = m.VertexRing(edge)
+	require.Len(t, ring, 4)
+	assert.Equal(t, Point{X: 3, Y: 0}, m.Vertices[ring[0]])
+	assert.Equal(t, Point{X: 1, Y: 0}, m.Vertices[ring[3]])
+	assert.Len(t, m.VertexTriangles(edge), 3)
+
+	corner := vertex(0, 0)
+	assert.Len(t, m.VertexRing(corner), 3)
+	assert.Len(t, m.VertexTriangles(corner), 2)
+}
+
+func TestMesh_flip(t *testing.T) {
+	a, b, c, d := Point{X: 0, Y: 0}, Point{X: 2, Y: 0}, Point{X: 2, Y: 1}, Point{X: 0, Y: 1}
+	m := NewMesh([]Triangle{{A: a, B: b, C: c}, {A: a, B: c, C: d}})
+	assertMeshConsistent(t, m)
+	diagonal := -1
+	for h := 0; h < 6; h++ {
+		if m.Twin(h) >= 0 {
+			diagonal = h
+			break
+		}
+	}
+	require.GreaterOrEqual(t, diagonal, 0)
+	assert.False(t, m.Flip(m.Prev(diagonal)))
+	require.True(t, m.Flip(diagonal))
+	assertMeshConsistent(t, m)
+	lines := m.ToLines()
+	assert.Len(t, lines, 5)
+	assert.Contains(t, triangleEdgeSet(m.ToTriangles()), [2]Point{d, b})
+	assert.InDelta(t, 2, trianglesArea(m.ToTriangles()), 1e-12)
+	for v := range m.Vertices {
+		assert.True(t, m.IsBoundaryVertex(v))
+		assert.Len(t, m.VertexRing(v), len(m.VertexTriangles(v))+1)
+	}
+
+	// a concave quadrilateral can't be flipped
+	e := Point{X: 1, Y: 0.2}
+	m = NewMesh([]Triangle{{A: a, B: e, C: d}, {A: e, B: b, C: d}})
+	for h := 0; h < 6; h++ {
+		if m.Twin(h) >= 0 {
+			assert.False(t, m.Flip(h))
+		}
+	}
+}
+
+func TestMesh_flipKeepsRings(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	var pts []Point
+	for i := 0; i < 60; i++ {
+		pts = append(pts, Point{X: rng.Float64(), Y: rng.Float64()})
+	}
+	m := DelaunayMesh(pts)
+	area := trianglesArea(m.ToTriangles())
+	for i := 0; i < 500; i++ {
+		m.Flip(rng.Intn(3 * len(m.Triangles)))
+	}
+	assertMeshConsistent(t, m)
+	assert.InDelta(t, area, trianglesArea(m.ToTriangles()), 1e-9)
+	for v := range m.Vertices {
+		ring := m.VertexRing(v)
+		tris := m.VertexTriangles(v)
+		if m.IsBoundaryVertex(v) {
+			assert.Len(t, ring, len(tris)+1)
+		} else {
+			assert.Len(t, ring, len(tris))
+		}
+	}
+	// every triangle is found around each of its vertices
+	for i, tri := range m.Triangles {
+		for _, v := range tri {
+			assert.Contains(t, m.VertexTriangles(v), i)
+		}
+	}
+}
+
+func TestMesh_polygon(t *testing.T) {
+	outer := Rect{X: 0, Y: 0, W: 10, H: 10}.ToCurve()
+	hole := Circle{Center: Point{X: 5, Y: 5}, Radius: 2}.ToCurve(12)
+	m := PolygonMesh([]Curve{outer, hole}, EvenOdd)
+	assertMeshConsistent(t, m)
+	assert.Len(t, m.Vertices, 16)
+	boundary := m.BoundaryEdges()
+	assert.Len(t, boundary, 16)
+	// the boundary runs around the hole clockwise
+	var around float64
+	for _, h := range boundary {
+		l := m.Edge(h)
+		if Distance(l.P, Point{X: 5, Y: 5}) < 3 {
+			around += orient2(Point{X: 5, Y: 5}, l.P, l.Q)
+		}
+	}
+	assert.Less(t, around, 0.0)
+	assert.InDelta(t, 100-hole.Area(), trianglesArea(m.ToTriangles()), 1e-9)
+	// an annulus has V - E + F = 0
+	assert.Equal(t, 0, len(m.Vertices)-len(m.ToLines())+len(m.Triangles))
+}
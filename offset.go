@@ -0,0 +1,202 @@
+package gaul
+
+import "math"
+
+const (
+	defaultOffsetResolution = 64
+	defaultMiterLimit       = 2
+)
+
+// JoinStyle selects how offset edges are connected around convex corners
+type JoinStyle int
+
+const (
+	JoinMiter JoinStyle = iota
+	JoinRound
+	JoinBevel
+)
+
+// CapStyle selects how the ends of an offset open curve are closed
+type CapStyle int
+
+const (
+	CapButt CapStyle = iota
+	CapRound
+	CapSquare
+)
+
+// OffsetOptions configures [Curve.Offset] and [OffsetCurves]. The zero value gives
+// mitered joins with a miter limit of 2 and butt caps.
+type OffsetOptions struct {
+	Join JoinStyle
+	Cap  CapStyle
+	// MiterLimit is the furthest a miter tip may be from its vertex, as a multiple of
+	// the offset distance. Sharper corners are beveled instead. Defaults to 2.
+	MiterLimit float64
+	// Resolution is the number of segments a full circle is divided into for round
+	// joins and caps. Defaults to 64.
+	Resolution int
+}
+
+func (o OffsetOptions) miterLimit() float64 {
+	if o.MiterLimit <= 0 {
+		return defaultMiterLimit
+	}
+	return math.Max(o.MiterLimit, 1)
+}
+
+func (o OffsetOptions) resolution() int {
+	if o.Resolution < 3 {
+		return defaultOffsetResolution
+	}
+	return o.Resolution
+}
+
+// Offset calculates the parallel outline of the curve at a given distance.
+//
+// For a closed curve a positive distance grows the enclosed region and a negative
+// distance shrinks it, whichever way the curve is wound. An inset may split into
+// several pieces or vanish entirely, in which case the result is empty. For an open
+// curve the result is the outline of a stroke of width 2*|distance| along the curve,
+// with ends shaped by opts.Cap.
+//
+// The result uses the same conventions as [BooleanCurves]: closed curves with outer
+// boundaries counterclockwise and holes clockwise.
+func (c *Curve) Offset(distance float64, opts OffsetOptions) []Curve {
+	if c.Closed {
+		return OffsetCurves([]Curve{*c}, NonZero, distance, opts)
+	}
+	pts := dedupeConsecutivePoints(c.Points)
+	if len(pts) == 0 || distance == 0 {
+		return nil
+	}
+	d := math.Abs(distance)
+	if len(pts) == 1 {
+		return []Curve{Circle{Center: pts[0], Radius: d}.ToCurve(opts.resolution())}
+	}
+	var raw []Point
+	raw = appendOffsetPath(raw, pts, false, d, opts)
+	raw = appendCap(raw, pts[len(pts)-2], pts[len(pts)-1], d, opts)
+	reversed := make([]Point, len(pts))
+	for i, p := range pts {
+		reversed[len(pts)-1-i] = p
+	}
+	raw = appendOffsetPath(raw, reversed, false, d, opts)
+	raw = appendCap(raw, pts[1], pts[0], d, opts)
+	return BooleanCurves([]Curve{{Points: raw, Closed: true}}, nil, BoolUnion, Positive)
+}
+
+// OffsetCurves offsets a region described by closed curves (outer boundaries and
+// holes, interpreted with the given fill rule). A positive distance grows the region
+// and shrinks its holes, a negative distance does the opposite. Pieces that vanish are
+// dropped and pieces that merge are joined, see [Curve.Offset].
+func OffsetCurves(curves []Curve, rule FillRule, distance float64, opts OffsetOptions) []Curve {
+	region := BooleanCurves(curves, nil, BoolUnion, rule)
+	if distance == 0 || len(region) == 0 {
+		return region
+	}
+	// Every loop of a normalized region has its interior on the left, so moving each
+	// one to its right grows the region. The raw outlines overlap and fold over where
+	// the offset is larger than local features; keeping only positively wound areas
+	// resolves both.
+	raw := make([]Curve, 0, len(region))
+	for _, loop := range region {
+		pts := appendOffsetPath(nil, loop.Points, true, distance, opts)
+		raw = append(raw, Curve{Points: pts, Closed: true})
+	}
+	return BooleanCurves(raw, nil, BoolUnion, Positive)
+}
+
+// appendOffsetPath appends the points of pts shifted to the right by d (to the left
+// for negative d), with joins between consecutive segments
+func appendOffsetPath(out []Point, pts []Point, closed bool, d float64, opts OffsetOptions) []Point {
+	n := len(pts)
+	normal := func(i int) Vec2 {
+		return Vec2FromPoints(pts[i], pts[(i+1)%n]).UnitNormal()
+	}
+	if !closed {
+		out = append(out, offsetPoint(pts[0], normal(0), d))
+	}
+	first, last := 0, n
+	if !closed {
+		first, last = 1, n-1
+	}
+	for i := first; i < last; i++ {
+		prev := (i + n - 1) % n
+		out = appendJoin(out, pts[prev], pts[i], pts[(i+1)%n], normal(prev), normal(i), d, opts)
+	}
+	if !closed {
+		out = append(out, offsetPoint(pts[n-1], normal(n-2), d))
+	}
+	return out
+}
+
+func offsetPoint(p Point, n Vec2, d float64) Point {
+	return Point{X: p.X + d*n.X, Y: p.Y + d*n.Y}
+}
+
+// appendJoin appends the offset points around vertex b, between the edges a-b and
+// b-c whose right-hand unit normals are n1 and n2
+func appendJoin(out []Point, a, b, c Point, n1, n2 Vec2, d float64, opts OffsetOptions) []Point {
+	p1 := offsetPoint(b, n1, d)
+	p2 := offsetPoint(b, n2, d)
+	ab := Vec2FromPoints(a, b)
+	bc := Vec2FromPoints(b, c)
+	cross := ab.X*bc.Y - ab.Y*bc.X
+	if math.Abs(cross) <= 1e-12*ab.Mag()*bc.Mag() && ab.Dot(bc) > 0 {
+		return append(out, p1)
+	}
+	if cross*d < 0 {
+		// the offset edges overlap on this side; routing through the vertex keeps the
+		// winding of the folded part negative so it is discarded later
+		return append(out, p1, b, p2)
+	}
+	switch opts.Join {
+	case JoinRound:
+		return appendArc(append(out, p1), b, n1, n2, d, opts.resolution())
+	case JoinMiter:
+		cosHalf := math.Sqrt(math.Max(0, (1+n1.Dot(n2))/2))
+		if cosHalf > 0 && 1/cosHalf <= opts.miterLimit() {
+			m := n1.Add(n2).Normalize().Scale(d / cosHalf)
+			return append(out, p1, Point{X: b.X + m.X, Y: b.Y + m.Y}, p2)
+		}
+	}
+	return append(out, p1, p2)
+}
+
+// appendArc appends points on the circle of radius |d| around center, sweeping from
+// direction d*n1 to d*n2 (exclusive of the start, inclusive of the end)
+func appendArc(out []Point, center Point, n1, n2 Vec2, d float64, resolution int) []Point {
+	start := math.Atan2(d*n1.Y, d*n1.X)
+	sweep := math.Atan2(n1.X*n2.Y-n1.Y*n2.X, n1.Dot(n2))
+	if math.Abs(sweep) >= Pi-1e-12 {
+		// a full reversal: sweep away from the segment, which lies on the -n side
+		sweep = math.Copysign(Pi, d)
+	}
+	steps := int(math.Ceil(math.Abs(sweep) / (Tau / float64(resolution))))
+	r := math.Abs(d)
+	for i := 1; i <= steps; i++ {
+		a := start + sweep*float64(i)/float64(steps)
+		out = append(out, Point{X: center.X + r*math.Cos(a), Y: center.Y + r*math.Sin(a)})
+	}
+	return out
+}
+
+// appendCap closes the stroke outline at the end point b of the segment a-b, going
+// from the right side to the left side
+func appendCap(out []Point, a, b Point, d float64, opts OffsetOptions) []Point {
+	dir := Vec2FromPoints(a, b).Normalize()
+	n := Vec2FromPoints(a, b).UnitNormal()
+	switch opts.Cap {
+	case CapRound:
+		return appendArc(out, b, n, n.Scale(-1), d, opts.resolution())
+	case CapSquare:
+		ext := dir.Scale(d)
+		right := offsetPoint(b, n, d)
+		left := offsetPoint(b, n, -d)
+		return append(out,
+			Point{X: right.X + ext.X, Y: right.Y + ext.Y},
+			Point{X: left.X + ext.X, Y: left.Y + ext.Y})
+	}
+	return out
+}
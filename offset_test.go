@@ -0,0 +1,141 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurve_Offset_squareJoins(t *testing.T) {
+	res := 256
+	tests := []struct {
+		name  string
+		join  JoinStyle
+		area  float64
+		delta float64
+	}{
+		{"miter", JoinMiter, 16, 1e-9},
+		{"bevel", JoinBevel, 14, 1e-9},
+		{"round", JoinRound, 12 + math.Pi, 1e-3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sq := square(0, 0, 2)
+			got := sq.Offset(1, OffsetOptions{Join: tt.join, Resolution: res})
+			require.Len(t, got, 1)
+			assert.InDelta(t, tt.area, signedAreaSum(got), tt.delta)
+		})
+	}
+}
+
+func TestCurve_Offset_miterLimit(t *testing.T) {
+	// a 90 degree corner needs a miter ratio of sqrt(2)
+	sq := square(0, 0, 2)
+	got := sq.Offset(1, OffsetOptions{Join: JoinMiter, MiterLimit: 1.4})
+	assert.InDelta(t, 14, signedAreaSum(got), 1e-9)
+	got = sq.Offset(1, OffsetOptions{Join: JoinMiter, MiterLimit: 1.5})
+	assert.InDelta(t, 16, signedAreaSum(got), 1e-9)
+}
+
+func TestCurve_Offset_inset(t *testing.T) {
+	sq := square(0, 0, 2)
+	sq.Reverse()
+	got := sq.Offset(-0.5, OffsetOptions{})
+	require.Len(t, got, 1)
+	assert.InDelta(t, 1, signedAreaSum(got), 1e-9)
+	assert.Greater(t, got[0].SignedArea(), 0.0)
+
+	assert.Empty(t, sq.Offset(-1.1, OffsetOptions{}))
+}
+
+func TestCurve_Offset_insetSplits(t *testing.T) {
+	// two 2x2 squares joined by a corridor 0.4 wide
+	dumbbell := Curve{Closed: true, Points: []Point{
+		{0, 0}, {2, 0}, {2, 0.8}, {4, 0.8}, {4, 0}, {6, 0},
+		{6, 2}, {4, 2}, {4, 1.2}, {2, 1.2}, {2, 2}, {0, 2},
+	}}
+	got := dumbbell.Offset(-0.3, OffsetOptions{})
+	require.Len(t, got, 2)
+	for _, c := range got {
+		assert.InDelta(t, 1.4*1.4, c.Area(), 1e-9)
+	}
+	got = dumbbell.Offset(-0.1, OffsetOptions{})
+	require.Len(t, got, 1)
+}
+
+func TestCurve_Offset_outsetMerges(t *testing.T) {
+	got := OffsetCurves([]Curve{square(0, 0, 1), square(1.5, 0, 1)}, NonZero, 0.5, OffsetOptions{})
+	require.Len(t, got, 1)
+	assert.InDelta(t, 3.5*2, signedAreaSum(got), 1e-9)
+}
+
+func TestOffsetCurves_holes(t *testing.T) {
+	ring := []Curve{square(0, 0, 4), square(1, 1, 2)}
+	got := OffsetCurves(ring, EvenOdd, 0.5, OffsetOptions{})
+	require.Len(t, got, 2)
+	assert.InDelta(t, 25-1, signedAreaSum(got), 1e-9)
+	got = OffsetCurves(ring, EvenOdd, 1.5, OffsetOptions{})
+	require.Len(t, got, 1, "the hole closes up")
+	assert.InDelta(t, 49, signedAreaSum(got), 1e-9)
+}
+
+func TestCurve_Offset_openCaps(t *testing.T) {
+	line := Curve{Points: []Point{{0, 0}, {10, 0}}}
+	tests := []struct {
+		name  string
+		cap   CapStyle
+		area  float64
+		delta float64
+	}{
+		{"butt", CapButt, 20, 1e-9},
+		{"square", CapSquare, 24, 1e-9},
+		{"round", CapRound, 20 + math.Pi, 1e-3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := line.Offset(1, OffsetOptions{Cap: tt.cap, Resolution: 256})
+			require.Len(t, got, 1)
+			assert.InDelta(t, tt.area, signedAreaSum(got), tt.delta)
+		})
+	}
+}
+
+func TestCurve_Offset_openZigzag(t *testing.T) {
+	zigzag := Curve{Points: []Point{{0, 0}, {1, 1}, {2, 0}, {3, 1}, {4, 0}}}
+	got := zigzag.Offset(0.4, OffsetOptions{Join: JoinRound, Cap: CapRound})
+	require.Len(t, got, 1)
+	for _, p := range got[0].Points {
+		d := math.Inf(1)
+		for i := 0; i < len(zigzag.Points)-1; i++ {
+			d = math.Min(d, Line{P: zigzag.Points[i], Q: zigzag.Points[i+1]}.SDF(p))
+		}
+		assert.InDelta(t, 0.4, d, 1e-3)
+	}
+}
+
+func TestCurve_Offset_insetKeepsDistance(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for i := 0; i < 20; i++ {
+		star := randomStar(rng, Point{}, 12+rng.Intn(12))
+		d := 0.05 + 0.1*rng.Float64()
+		got := star.Offset(-d, OffsetOptions{Join: JoinRound, Resolution: 128})
+		require.NotEmpty(t, got)
+		for _, c := range got {
+			for _, p := range c.Points {
+				assert.GreaterOrEqual(t, boundaryDistance(star, p), d*(1-1e-3))
+			}
+		}
+	}
+}
+
+func boundaryDistance(c Curve, p Point) float64 {
+	d := math.Inf(1)
+	n := len(c.Points)
+	for i := 0; i < n; i++ {
+		d = math.Min(d, Line{P: c.Points[i], Q: c.Points[(i+1)%n]}.SDF(p))
+	}
+	return d
+}
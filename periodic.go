@@ -0,0 +1,204 @@
+package gaul
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// PeriodicDelaunayTriangles returns the Delaunay triangulation of sites on the torus
+// made by wrapping bounds around: a site near one side is a neighbour of the sites
+// near the opposite one. Sites are taken modulo the size of bounds, and duplicates
+// after wrapping are merged.
+//
+// Each triangle of the torus appears once, as one of its translates by multiples of
+// bounds.W and bounds.H, so some corners lie outside bounds. Repeated over the plane
+// the triangles tile it; drawn with their copies offset by ±W and ±H and clipped to
+// bounds they make a seamless tile. n unique sites give 2n triangles, each
+// counterclockwise. The result is nil if bounds has no area or there are no sites.
+func PeriodicDelaunayTriangles(bounds Rect, sites []Point) []Triangle {
+	if bounds.W <= 0 || bounds.H <= 0 || len(sites) == 0 {
+		return nil
+	}
+	pt := newPeriodicTriangulation(bounds, sites)
+	out := make([]Triangle, len(pt.tris))
+	for i, t := range pt.tris {
+		out[i] = Triangle{A: pt.point(t[0]), B: pt.point(t[1]), C: pt.point(t[2])}
+	}
+	return out
+}
+
+// PeriodicVoronoiWithRect computes the Voronoi diagram of sites on the torus made by
+// wrapping bounds around, as the dual of [PeriodicDelaunayTriangles]. Each returned
+// [Curve] is the closed, counterclockwise cell of sites[i], whole and around the site:
+// cells near the sides of bounds cross them, and continue on the opposite side in the
+// copies of the diagram next to it. Drawing each cell with its copies offset by ±W and
+// ±H and clipping to bounds renders a seamless tile. The cells tile the torus, so
+// their areas add up to that of bounds. Sites outside bounds are wrapped into it, and
+// duplicates after wrapping get identical cells, up to translation.
+func PeriodicVoronoiWithRect(bounds Rect, sites []Point) ([]Curve, error) {
+	if bounds.W <= 0 || bounds.H <= 0 {
+		return nil, errors.New("gaul PeriodicVoronoiWithRect: bounds width and height must be positive")
+	}
+	if len(sites) == 0 {
+		return nil, nil
+	}
+	pt := newPeriodicTriangulation(bounds, sites)
+	cells := make([][]Point, len(pt.sites))
+	for _, t := range pt.tris {
+		center := circleThrough(pt.point(t[0]), pt.point(t[1]), pt.point(t[2])).Center
+		// move the circumcenter next to the copy of each corner in the tile
+		for _, v := range t {
+			cells[v.site] = append(cells[v.site], Point{X: center.X - float64(v.dx)*bounds.W, Y: center.Y - float64(v.dy)*bounds.H})
+		}
+	}
+	for i, pts := range cells {
+		s := pt.sites[i]
+		sort.Slice(pts, func(a, b int) bool {
+			return math.Atan2(pts[a].Y-s.Y, pts[a].X-s.X) < math.Atan2(pts[b].Y-s.Y, pts[b].X-s.X)
+		})
+		cells[i] = voronoiDedupeConsecutivePolygonVerts(pts)
+	}
+
+	out := make([]Curve, len(sites))
+	for i, p := range sites {
+		u := pt.index[pt.wrap(p)]
+		// shift the cell from the wrapped site to the one given
+		dx, dy := p.X-pt.sites[u].X, p.Y-pt.sites[u].Y
+		c := Curve{Closed: true, Points: make([]Point, len(cells[u]))}
+		for k, q := range cells[u] {
+			c.Points[k] = Point{X: q.X + dx, Y: q.Y + dy}
+		}
+		out[i] = c
+	}
+	return out, nil
+}
+
+// periodicVertex is the copy of a site translated by (dx, dy) tiles
+type periodicVertex struct {
+	site, dx, dy int
+}
+
+type periodicTriangulation struct {
+	bounds Rect
+	sites  []Point // unique, wrapped into the half-open tile
+	index  map[Point]int
+	tris   [][3]periodicVertex
+}
+
+// newPeriodicTriangulation triangulates the sites padded with the copies of them
+// within a margin around bounds, and keeps one translate of each triangle: the one in
+// which the corner with the smallest site, and the smallest offset among those, is
+// in the tile. The triangles are those of the torus if all their circumcircles lie
+// within the padding, and there are 2n of them; otherwise the margin is doubled.
+//
+// Cocircular sites, as on a grid, have more than one Delaunay triangulation, and the
+// copies of them need not be split alike. The triangulation is of the sites moved by
+// a tiny fixed jitter that every copy of a site shares, and of the whole plane
+// sheared a tiny bit, so it is the same in each tile; the triangles are reported with
+// the sites as given. The shear keeps the copies of a site from forming a rectangular
+// lattice, whose squares the jitter can't break ties in. Each retry takes another
+// jitter and shear, and after periodicAttempts of them the last triangulation is
+// kept even if it is off.
+func newPeriodicTriangulation(bounds Rect, sites []Point) *periodicTriangulation {
+	pt := &periodicTriangulation{bounds: bounds, index: make(map[Point]int)}
+	for _, p := range sites {
+		w := pt.wrap(p)
+		if _, ok := pt.index[w]; !ok {
+			pt.index[w] = len(pt.sites)
+			pt.sites = append(pt.sites, w)
+		}
+	}
+	n := len(pt.sites)
+	margin := 2 * math.Sqrt(bounds.W*bounds.H/float64(n))
+	eps := 1e-9 * math.Max(bounds.W, bounds.H)
+	for seed := int64(1); ; {
+		rng := rand.New(rand.NewSource(seed))
+		jitter := make([]Vec2, n)
+		for i := range jitter {
+			jitter[i] = Vec2{X: eps * (2*rng.Float64() - 1), Y: eps * (2*rng.Float64() - 1)}
+		}
+		// both positive, so the sheared tile is never a rectangle
+		sx, sy := 1e-9*(1+rng.Float64()), 1e-9*(1+rng.Float64())
+		padded := Rect{X: bounds.X - margin, Y: bounds.Y - margin, W: bounds.W + 2*margin, H: bounds.H + 2*margin}
+		copies := make(map[Point]periodicVertex)
+		var pts []Point
+		kx, ky := int(math.Ceil(margin/bounds.W)), int(math.Ceil(margin/bounds.H))
+		for i := range pt.sites {
+			for dy := -ky; dy <= ky; dy++ {
+				for dx := -kx; dx <= kx; dx++ {
+					v := periodicVertex{site: i, dx: dx, dy: dy}
+					p := pt.point(v)
+					p = Point{X: p.X + jitter[i].X, Y: p.Y + jitter[i].Y}
+					p = Point{X: p.X + sx*(p.Y-bounds.Y), Y: p.Y + sy*(p.X-bounds.X)}
+					if padded.ContainsPoint(p) {
+						copies[p] = v
+						pts = append(pts, p)
+					}
+				}
+			}
+		}
+		pt.tris = pt.tris[:0]
+		valid := true
+		for _, tri := range DelaunayTriangles(pts) {
+			t := [3]periodicVertex{copies[tri.A], copies[tri.B], copies[tri.C]}
+			if !periodicCanonical(t) {
+				continue
+			}
+			c := circleThrough(tri.A, tri.B, tri.C)
+			if c.Center.X-c.Radius < padded.X || c.Center.X+c.Radius > padded.X+padded.W ||
+				c.Center.Y-c.Radius < padded.Y || c.Center.Y+c.Radius > padded.Y+padded.H {
+				valid = false
+				break
+			}
+			pt.tris = append(pt.tris, t)
+		}
+		switch {
+		case !valid:
+			margin *= 2
+		case len(pt.tris) == 2*n || seed == periodicAttempts:
+			return pt
+		default:
+			// the jitter left copies of some sites cocircular; try another
+			seed++
+		}
+	}
+}
+
+// periodicAttempts caps the jitters [newPeriodicTriangulation] tries
+const periodicAttempts = 8
+
+// periodicCanonical reports whether t is the translate of its triangle that
+// [newPeriodicTriangulation] keeps
+func periodicCanonical(t [3]periodicVertex) bool {
+	best := t[0]
+	for _, v := range t[1:] {
+		if v.site < best.site || (v.site == best.site && (v.dx < best.dx || (v.dx == best.dx && v.dy < best.dy))) {
+			best = v
+		}
+	}
+	return best.dx == 0 && best.dy == 0
+}
+
+// wrap moves p into the half-open tile [X, X+W) × [Y, Y+H)
+func (pt *periodicTriangulation) wrap(p Point) Point {
+	b := pt.bounds
+	mod := func(v, lo, size float64) float64 {
+		v = math.Mod(v-lo, size)
+		if v < 0 {
+			v += size
+		}
+		if v >= size {
+			// -tiny + size rounds to size
+			v = 0
+		}
+		return lo + v
+	}
+	return Point{X: mod(p.X, b.X, b.W), Y: mod(p.Y, b.Y, b.H)}
+}
+
+func (pt *periodicTriangulation) point(v periodicVertex) Point {
+	s := pt.sites[v.site]
+	return Point{X: s.X + float64(v.dx)*pt.bounds.W, Y: s.Y + float64(v.dy)*pt.bounds.H}
+}
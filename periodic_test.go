@@ -0,0 +1,133 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// torusOffsets are the translates of the tile that sites within it can reach
+func torusOffsets(b Rect, k int) []Vec2 {
+	var out []Vec2
+	for i := -k; i <= k; i++ {
+		for j := -k; j <= k; j++ {
+			out = append(out, Vec2{X: float64(i) * b.W, Y: float64(j) * b.H})
+		}
+	}
+	return out
+}
+
+func randomSitesIn(rng *rand.Rand, b Rect, n int) []Point {
+	var out []Point
+	for i := 0; i < n; i++ {
+		out = append(out, Point{X: b.X + b.W*rng.Float64(), Y: b.Y + b.H*rng.Float64()})
+	}
+	return out
+}
+
+func TestPeriodicDelaunayTriangles(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	b := Rect{X: 1, Y: -2, W: 3, H: 2}
+	for _, n := range []int{1, 2, 5, 60} {
+		sites := randomSitesIn(rng, b, n)
+		tris := PeriodicDelaunayTriangles(b, sites)
+		require.Len(t, tris, 2*n)
+		var area float64
+		for _, tri := range tris {
+			require.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+			area += orient2(tri.A, tri.B, tri.C) / 2
+			// no copy of any site lies in the circumcircle
+			for _, s := range sites {
+				for _, o := range torusOffsets(b, 3) {
+					assert.False(t, inCircumcircle(tri.A, tri.B, tri.C, Point{X: s.X + o.X, Y: s.Y + o.Y}))
+				}
+			}
+		}
+		assert.InDelta(t, b.W*b.H, area, 1e-9, "n=%d", n)
+	}
+	assert.Nil(t, PeriodicDelaunayTriangles(b, nil))
+}
+
+func TestPeriodicVoronoiWithRect(t *testing.T) {
+	rng := rand.New(rand.NewSource(47))
+	b := Rect{X: 0, Y: 0, W: 2, H: 1}
+	sites := randomSitesIn(rng, b, 40)
+	cells, err := PeriodicVoronoiWithRect(b, sites)
+	require.NoError(t, err)
+	require.Len(t, cells, len(sites))
+	var sum float64
+	for i, c := range cells {
+		sum += c.Area()
+		assert.Greater(t, c.SignedArea(), 0.0)
+		assert.True(t, c.ContainsPoint(sites[i], NonZero))
+	}
+	assert.InDelta(t, b.W*b.H, sum, 1e-9)
+
+	// a point of the tile lies in a copy of the cell of its nearest site, with
+	// distances measured around the torus
+	for k := 0; k < 300; k++ {
+		p := randomSitesIn(rng, b, 1)[0]
+		nearest, best := -1, math.Inf(1)
+		for i, s := range sites {
+			for _, o := range torusOffsets(b, 1) {
+				if d := Distance(p, Point{X: s.X + o.X, Y: s.Y + o.Y}); d < best {
+					nearest, best = i, d
+				}
+			}
+		}
+		found := false
+		for _, o := range torusOffsets(b, 1) {
+			c := Curve{Closed: true, Points: append([]Point(nil), cells[nearest].Points...)}
+			c.Translate(-o.X, -o.Y)
+			found = found || c.ContainsPoint(p, NonZero)
+		}
+		assert.True(t, found, "point %v", p)
+	}
+}
+
+func TestPeriodicVoronoiWithRect_wrapping(t *testing.T) {
+	b := Rect{X: 0, Y: 0, W: 4, H: 4}
+	var sites []Point
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			sites = append(sites, Point{X: float64(i), Y: float64(j)})
+		}
+	}
+	// a site on the far side is the same as the one at the origin
+	sites = append(sites, Point{X: 4, Y: 0}, Point{X: -1, Y: 5})
+	cells, err := PeriodicVoronoiWithRect(b, sites)
+	require.NoError(t, err)
+	for i, c := range cells {
+		require.Len(t, c.Points, 4)
+		assert.InDelta(t, 1, c.Area(), 1e-9)
+		bb := c.Boundary()
+		assert.InDelta(t, sites[i].X-0.5, bb.X, 1e-9)
+		assert.InDelta(t, sites[i].Y-0.5, bb.Y, 1e-9)
+	}
+	assert.Len(t, PeriodicDelaunayTriangles(b, sites), 32)
+
+	one, err := PeriodicVoronoiWithRect(b, []Point{{X: 1, Y: 3}})
+	require.NoError(t, err)
+	assert.InDelta(t, 16, one[0].Area(), 1e-9)
+
+	_, err = PeriodicVoronoiWithRect(Rect{W: 1}, sites)
+	assert.Error(t, err)
+}
+
+func TestPeriodicVoronoiWithRect_oneSite(t *testing.T) {
+	// the copies of a single site form a square lattice, cocircular everywhere
+	for _, b := range []Rect{{W: 10, H: 10}, {X: -3, Y: 2, W: 1, H: 1}, {W: 4, H: 2}} {
+		for _, p := range []Point{b.Center(), {X: b.X, Y: b.Y}, {X: b.X + 0.3*b.W, Y: b.Y + 0.9*b.H}} {
+			tris := PeriodicDelaunayTriangles(b, []Point{p})
+			require.Len(t, tris, 2)
+			assert.InDelta(t, b.W*b.H, tris[0].Area()+tris[1].Area(), 1e-9)
+			cells, err := PeriodicVoronoiWithRect(b, []Point{p})
+			require.NoError(t, err)
+			assert.InDelta(t, b.W*b.H, cells[0].Area(), 1e-9)
+			assert.True(t, cells[0].ContainsPoint(p, NonZero))
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package gaul
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// PowerDiagramWithRect computes the power diagram, or Laguerre tessellation, of
+// weighted sites clipped to bounds. It is the Voronoi diagram for the power distance
+// |x - sites[i]|² - weights[i], so raising the weight of a site grows its cell at the
+// expense of its neighbours, which makes the cell areas controllable, as in Voronoi
+// treemaps. With equal weights it is the plain Voronoi diagram of [VoronoiWithRect].
+//
+// Each returned [Curve] is closed, convex and counterclockwise, and is the cell of
+// sites[i]. Unlike a Voronoi cell, a power cell need not contain its site, and a site
+// whose weight is small enough next to its neighbours has an empty cell, returned as a
+// curve with no points. Duplicate sites with equal weights get identical cells; of
+// duplicates with different weights only the heaviest has a cell. Sites must lie
+// inside or on bounds.
+func PowerDiagramWithRect(bounds Rect, sites []Point, weights []float64) ([]Curve, error) {
+	if bounds.W <= 0 || bounds.H <= 0 {
+		return nil, errors.New("gaul PowerDiagramWithRect: bounds width and height must be positive")
+	}
+	if len(weights) != len(sites) {
+		return nil, errors.New("gaul PowerDiagramWithRect: need one weight per site")
+	}
+	for i, p := range sites {
+		if !bounds.ContainsPoint(p) {
+			return nil, fmt.Errorf("gaul PowerDiagramWithRect: site %d is not inside bounds", i)
+		}
+	}
+	return powerCells(ensurePolygonCCW(bounds.ToCurve().Points), bounds, sites, weights), nil
+}
+
+// PowerDiagramWithCurve is [PowerDiagramWithRect] within a convex boundary, with the
+// same requirements on it as [VoronoiWithCurve]. Voronoi treemaps split each cell of
+// a power diagram this way.
+func PowerDiagramWithCurve(boundary Curve, sites []Point, weights []float64) ([]Curve, error) {
+	poly, br, err := voronoiConvexBoundary("PowerDiagramWithCurve", boundary)
+	if err != nil {
+		return nil, err
+	}
+	if len(weights) != len(sites) {
+		return nil, errors.New("gaul PowerDiagramWithCurve: need one weight per site")
+	}
+	for i, p := range sites {
+		if !voronoiPointInOrOnConvexCCW(p, poly) {
+			return nil, fmt.Errorf("gaul PowerDiagramWithCurve: site %d is not inside boundary", i)
+		}
+	}
+	return powerCells(poly, br, sites, weights), nil
+}
+
+// powerCells cuts the convex polygon boundary down to the cell of each site, one
+// half-plane per other site. The other sites are visited ring by ring through a grid
+// over extent, from the site outwards, until the cell is too small for any farther one
+// to cut it given the largest weight, so evenly weighted sites take about linear time
+// overall.
+func powerCells(boundary []Point, extent Rect, sites []Point, weights []float64) []Curve {
+	n := len(sites)
+	if n == 0 {
+		return nil
+	}
+	maxWeight := math.Inf(-1)
+	for _, w := range weights {
+		maxWeight = math.Max(maxWeight, w)
+	}
+	side := int(math.Ceil(math.Sqrt(float64(n))))
+	h := math.Max(extent.W, extent.H) / float64(side)
+	cellOf := func(p Point) (int, int) {
+		i := int((p.X - extent.X) / h)
+		j := int((p.Y - extent.Y) / h)
+		return min(max(i, 0), side-1), min(max(j, 0), side-1)
+	}
+	grid := make([][]int, side*side)
+	for k, p := range sites {
+		i, j := cellOf(p)
+		grid[j*side+i] = append(grid[j*side+i], k)
+	}
+
+	out := make([]Curve, n)
+	for k, p := range sites {
+		poly := append([]Point(nil), boundary...)
+		ci, cj := cellOf(p)
+		for r := 0; len(poly) > 0 && r < side; r++ {
+			// the sites in ring r and beyond are at least (r-1)h from p
+			if lo := float64(r-1) * h; r > 1 && !powerCanCut(poly, p, weights[k], lo, maxWeight) {
+				break
+			}
+			for j := cj - r; j <= cj+r; j++ {
+				for i := ci - r; i <= ci+r; i++ {
+					if max(i-ci, ci-i, j-cj, cj-j) != r || i < 0 || j < 0 || i >= side || j >= side {
+						continue
+					}
+					for _, o := range grid[j*side+i] {
+						if o != k && len(poly) > 0 {
+							poly = powerClip(poly, p, weights[k], sites[o], weights[o])
+						}
+					}
+				}
+			}
+		}
+		out[k] = Curve{Closed: true}
+		if len(poly) >= 3 {
+			out[k].Points = poly
+		}
+	}
+	return out
+}
+
+// powerCanCut reports whether a site at distance at least lo from p, with weight at
+// most maxWeight, could cut the cell poly of p. The site at q cuts it where
+// 2(x-p)·(q-p) > |q-p|² + w - wq for some vertex x; with R the farthest vertex from p
+// the left side is at most 2R|q-p|, and the inequality can't hold once |q-p| ≥ R and
+// |q-p|² - 2R|q-p| + w - maxWeight ≥ 0.
+func powerCanCut(poly []Point, p Point, w, lo, maxWeight float64) bool {
+	var r float64
+	for _, v := range poly {
+		r = math.Max(r, Distance(p, v))
+	}
+	return lo < r || lo*lo-2*r*lo+w-maxWeight < 0
+}
+
+// powerClip keeps the part of the convex polygon poly that is at least as close to
+// p, weighted w, as to q, weighted wq, in power distance
+func powerClip(poly []Point, p Point, w float64, q Point, wq float64) []Point {
+	d := Vec2FromPoints(p, q)
+	c := d.Dot(d) + w - wq
+	f := func(x Point) float64 {
+		return 2*((x.X-p.X)*d.X+(x.Y-p.Y)*d.Y) - c
+	}
+	var out []Point
+	add := func(x Point) {
+		if len(out) == 0 || out[len(out)-1] != x {
+			out = append(out, x)
+		}
+	}
+	prev := poly[len(poly)-1]
+	fp := f(prev)
+	for _, cur := range poly {
+		fc := f(cur)
+		if (fp <= 0) != (fc <= 0) {
+			add(prev.Lerp(cur, fp/(fp-fc)))
+		}
+		if fc <= 0 {
+			add(cur)
+		}
+		prev, fp = cur, fc
+	}
+	if len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package gaul
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerDiagramWithRect_twoSites(t *testing.T) {
+	bounds := Rect{X: 0, Y: 0, W: 4, H: 1}
+	sites := []Point{{X: 1, Y: 0.5}, {X: 3, Y: 0.5}}
+	// the cells meet where (x-1)² - w0 = (x-3)² - w1, at x = 2 + (w0-w1)/4
+	for _, w := range [][]float64{{0, 0}, {1, 0}, {0, 3}, {-2, 2}, {10, 10}} {
+		cells, err := PowerDiagramWithRect(bounds, sites, w)
+		require.NoError(t, err)
+		x := 2 + (w[0]-w[1])/4
+		assert.InDelta(t, x, cells[0].Area(), 1e-12, "weights %v", w)
+		assert.InDelta(t, 4-x, cells[1].Area(), 1e-12, "weights %v", w)
+	}
+
+	// the right cell swallows the left one, and its site with it
+	cells, err := PowerDiagramWithRect(bounds, sites, []float64{0, 10})
+	require.NoError(t, err)
+	assert.Empty(t, cells[0].Points)
+	assert.InDelta(t, 4, cells[1].Area(), 1e-12)
+	assert.True(t, cells[1].ContainsPoint(sites[0], NonZero))
+}
+
+func TestPowerDiagramWithRect_matchesVoronoi(t *testing.T) {
+	rng := rand.New(rand.NewSource(37))
+	bounds := Rect{X: -2, Y: 1, W: 5, H: 3}
+	var sites []Point
+	for i := 0; i < 200; i++ {
+		sites = append(sites, Point{X: bounds.X + bounds.W*rng.Float64(), Y: bounds.Y + bounds.H*rng.Float64()})
+	}
+	sites = append(sites, sites[7])
+	weights := make([]float64, len(sites))
+	for i := range weights {
+		weights[i] = 0.25
+	}
+	want, err := VoronoiWithRect(bounds, sites)
+	require.NoError(t, err)
+	got, err := PowerDiagramWithRect(bounds, sites, weights)
+	require.NoError(t, err)
+	require.Len(t, got, len(sites))
+	for i := range sites {
+		assert.InDelta(t, want[i].Area(), got[i].Area(), 1e-9)
+		assert.Greater(t, got[i].SignedArea(), 0.0)
+	}
+	assert.Equal(t, got[7], got[len(sites)-1])
+}
+
+func TestPowerDiagramWithCurve_weights(t *testing.T) {
+	rng := rand.New(rand.NewSource(41))
+	boundary := Curve{Closed: true, Points: []Point{{0, 0}, {6, 0}, {3, 5}}}
+	var sites []Point
+	var weights []float64
+	for len(sites) < 80 {
+		p := Point{X: 6 * rng.Float64(), Y: 5 * rng.Float64()}
+		if boundary.ContainsPoint(p, NonZero) {
+			sites = append(sites, p)
+			weights = append(weights, 0.1*rng.NormFloat64())
+		}
+	}
+	cells, err := PowerDiagramWithCurve(boundary, sites, weights)
+	require.NoError(t, err)
+	var sum float64
+	for i, c := range cells {
+		sum += c.Area()
+		// every point of a cell is no farther from its site in power distance than
+		// from any other
+		for _, x := range c.Points {
+			for j := range sites {
+				assert.LessOrEqual(t, SquaredDistance(x, sites[i])-weights[i], SquaredDistance(x, sites[j])-weights[j]+1e-9)
+			}
+		}
+	}
+	assert.InDelta(t, boundary.Area(), sum, 1e-9)
+
+	// growing one weight grows its cell
+	before := cells[0].Area()
+	weights[0] += 0.2
+	cells, err = PowerDiagramWithCurve(boundary, sites, weights)
+	require.NoError(t, err)
+	assert.Greater(t, cells[0].Area(), before)
+
+	_, err = PowerDiagramWithCurve(boundary, sites, weights[1:])
+	assert.Error(t, err)
+	_, err = PowerDiagramWithCurve(boundary, []Point{{X: 6, Y: 5}}, []float64{0})
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+package gaul
+
+import "math"
+
+// Robust geometric predicates after Shewchuk, "Adaptive Precision Floating-Point
+// Arithmetic and Fast Robust Geometric Predicates". Each determinant is first
+// evaluated in plain floating point; when its magnitude is below a bound on the
+// rounding error the sign is uncertain, and it is evaluated again exactly, as an
+// expansion: a sum of non-overlapping floats ordered by increasing magnitude. The
+// exact stage is only reached for (nearly) degenerate input, so the predicates cost
+// about the same as the plain formulas while their signs are always right.
+
+const (
+	machineEpsilon = 1.0 / (1 << 53)
+	orientErrBound = (3 + 16*machineEpsilon) * machineEpsilon
+	circleErrBound = (10 + 96*machineEpsilon) * machineEpsilon
+)
+
+// orient2 returns twice the signed area of triangle abc: positive if a, b, c turn
+// counterclockwise, negative if clockwise and zero if they are collinear. The sign is
+// exact; the magnitude is accurate to rounding.
+func orient2(a, b, c Point) float64 {
+	detLeft := (a.X - c.X) * (b.Y - c.Y)
+	detRight := (a.Y - c.Y) * (b.X - c.X)
+	det := detLeft - detRight
+	if math.Abs(det) >= orientErrBound*(math.Abs(detLeft)+math.Abs(detRight)) {
+		return det
+	}
+	return orient2Exact(a, b, c)
+}
+
+func orient2Exact(a, b, c Point) float64 {
+	bax, bay := twoDiff(b.X, a.X), twoDiff(b.Y, a.Y)
+	cax, cay := twoDiff(c.X, a.X), twoDiff(c.Y, a.Y)
+	det := expansionDiff(expansionProduct(bax, cay), expansionProduct(bay, cax))
+	return expansionEstimate(det)
+}
+
+// incircle returns a value that is positive if d lies inside the circle through a, b
+// and c when they are counterclockwise (outside when clockwise), negative if it lies
+// on the other side and zero if the four points are cocircular. The sign is exact.
+func incircle(a, b, c, d Point) float64 {
+	adx, ady := a.X-d.X, a.Y-d.Y
+	bdx, bdy := b.X-d.X, b.Y-d.Y
+	cdx, cdy := c.X-d.X, c.Y-d.Y
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if math.Abs(det) > circleErrBound*permanent {
+		return det
+	}
+	return incircleExact(a, b, c, d)
+}
+
+func incircleExact(a, b, c, d Point) float64 {
+	adx, ady := twoDiff(a.X, d.X), twoDiff(a.Y, d.Y)
+	bdx, bdy := twoDiff(b.X, d.X), twoDiff(b.Y, d.Y)
+	cdx, cdy := twoDiff(c.X, d.X), twoDiff(c.Y, d.Y)
+	lift := func(x, y []float64) []float64 {
+		return expansionSum(expansionProduct(x, x), expansionProduct(y, y))
+	}
+	cross := func(x1, y1, x2, y2 []float64) []float64 {
+		return expansionDiff(expansionProduct(x1, y2), expansionProduct(x2, y1))
+	}
+	det := expansionProduct(lift(adx, ady), cross(bdx, bdy, cdx, cdy))
+	det = expansionSum(det, expansionProduct(lift(bdx, bdy), cross(cdx, cdy, adx, ady)))
+	det = expansionSum(det, expansionProduct(lift(cdx, cdy), cross(adx, ady, bdx, bdy)))
+	return expansionEstimate(det)
+}
+
+// inCircumcircle reports whether p lies strictly inside the circumcircle of triangle ABC.
+// ABC may be clockwise or counterclockwise; degenerate (collinear) ABC yields false.
+// Both the orientation and the in-circle test are exact, so points on the circle are
+// never inside it.
+func inCircumcircle(a, b, c, p Point) bool {
+	o := orient2(a, b, c)
+	if o == 0 {
+		return false
+	}
+	det := incircle(a, b, c, p)
+	if o < 0 {
+		det = -det
+	}
+	return det > 0
+}
+
+// twoSum returns a+b as the rounded sum and its rounding error
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	y = (a - av) + (b - bv)
+	return x, y
+}
+
+// twoDiff returns a-b exactly, as a two-component expansion
+func twoDiff(a, b float64) []float64 {
+	x := a - b
+	bv := a - x
+	av := x + bv
+	y := (a - av) + (bv - b)
+	return []float64{y, x}
+}
+
+// growExpansion adds b to expansion e, dropping zero components
+func growExpansion(e []float64, b float64) []float64 {
+	out := make([]float64, 0, len(e)+1)
+	q := b
+	for _, v := range e {
+		var h float64
+		q, h = twoSum(q, v)
+		if h != 0 {
+			out = append(out, h)
+		}
+	}
+	if q != 0 || len(out) == 0 {
+		out = append(out, q)
+	}
+	return out
+}
+
+func expansionSum(e, f []float64) []float64 {
+	for _, v := range f {
+		e = growExpansion(e, v)
+	}
+	return e
+}
+
+func expansionDiff(e, f []float64) []float64 {
+	for _, v := range f {
+		e = growExpansion(e, -v)
+	}
+	return e
+}
+
+// scaleExpansion multiplies expansion e by b, dropping zero components
+func scaleExpansion(e []float64, b float64) []float64 {
+	out := make([]float64, 0, 2*len(e))
+	var q float64
+	for i, v := range e {
+		p := v * b
+		pe := math.FMA(v, b, -p)
+		if i == 0 {
+			q = p
+			if pe != 0 {
+				out = append(out, pe)
+			}
+			continue
+		}
+		var h float64
+		q, h = twoSum(q, pe)
+		if h != 0 {
+			out = append(out, h)
+		}
+		q, h = twoSum(p, q)
+		if h != 0 {
+			out = append(out, h)
+		}
+	}
+	if q != 0 || len(out) == 0 {
+		out = append(out, q)
+	}
+	return out
+}
+
+func expansionProduct(e, f []float64) []float64 {
+	var out []float64
+	for _, v := range f {
+		out = expansionSum(out, scaleExpansion(e, v))
+	}
+	if out == nil {
+		return []float64{0}
+	}
+	return out
+}
+
+// expansionEstimate returns the largest component of an expansion, which has its
+// sign and is within rounding of its value
+func expansionEstimate(e []float64) float64 {
+	return e[len(e)-1]
+}
@@ -0,0 +1,200 @@
+package gaul
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ratSign(x *big.Rat) float64 {
+	return float64(x.Sign())
+}
+
+// exactOrient and exactIncircle evaluate the predicates with rational arithmetic
+func exactOrient(a, b, c Point) float64 {
+	r := func(v float64) *big.Rat { return new(big.Rat).SetFloat64(v) }
+	bax, bay := new(big.Rat).Sub(r(b.X), r(a.X)), new(big.Rat).Sub(r(b.Y), r(a.Y))
+	cax, cay := new(big.Rat).Sub(r(c.X), r(a.X)), new(big.Rat).Sub(r(c.Y), r(a.Y))
+	det := new(big.Rat).Sub(new(big.Rat).Mul(bax, cay), new(big.Rat).Mul(bay, cax))
+	return ratSign(det)
+}
+
+func exactIncircle(a, b, c, d Point) float64 {
+	r := func(v float64) *big.Rat { return new(big.Rat).SetFloat64(v) }
+	sub := func(p, q float64) *big.Rat { return new(big.Rat).Sub(r(p), r(q)) }
+	mul := func(x, y *big.Rat) *big.Rat { return new(big.Rat).Mul(x, y) }
+	add := func(x, y *big.Rat) *big.Rat { return new(big.Rat).Add(x, y) }
+	adx, ady := sub(a.X, d.X), sub(a.Y, d.Y)
+	bdx, bdy := sub(b.X, d.X), sub(b.Y, d.Y)
+	cdx, cdy := sub(c.X, d.X), sub(c.Y, d.Y)
+	cross := func(x1, y1, x2, y2 *big.Rat) *big.Rat { return new(big.Rat).Sub(mul(x1, y2), mul(x2, y1)) }
+	det := mul(add(mul(adx, adx), mul(ady, ady)), cross(bdx, bdy, cdx, cdy))
+	det = add(det, mul(add(mul(bdx, bdx), mul(bdy, bdy)), cross(cdx, cdy, adx, ady)))
+	det = add(det, mul(add(mul(cdx, cdx), mul(cdy, cdy)), cross(adx, ady, bdx, bdy)))
+	return ratSign(det)
+}
+
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func TestOrient2_nearlyCollinear(t *testing.T) {
+	// points a few units in the last place off the diagonal through (12, 12) and
+	// (24, 24), where the plain determinant gets about half the signs wrong
+	ulp := math.Nextafter(0.5, 1) - 0.5
+	a, b := Point{X: 12, Y: 12}, Point{X: 24, Y: 24}
+	for i := 0; i < 32; i++ {
+		for j := 0; j < 32; j++ {
+			c := Point{X: 0.5 + float64(i)*ulp, Y: 0.5 + float64(j)*ulp}
+			assert.Equal(t, sign(float64(j-i)), sign(orient2(a, b, c)), "i=%d j=%d", i, j)
+			assert.Equal(t, sign(float64(i-j)), sign(orient2(b, a, c)))
+		}
+	}
+}
+
+func TestPredicates_agreeWithRationals(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	for n := 0; n < 2000; n++ {
+		// cocircular and collinear points, then rounded to floats
+		center := Point{X: rng.Float64() * 1e3, Y: rng.Float64() * 1e3}
+		radius := rng.Float64() * 10
+		var p [4]Point
+		for i := range p {
+			a := rng.Float64() * Tau
+			p[i] = Point{X: center.X + radius*math.Cos(a), Y: center.Y + radius*math.Sin(a)}
+		}
+		assert.Equal(t, exactIncircle(p[0], p[1], p[2], p[3]), sign(incircle(p[0], p[1], p[2], p[3])))
+		s := rng.Float64()
+		q := Point{X: p[0].X + s*(p[1].X-p[0].X), Y: p[0].Y + s*(p[1].Y-p[0].Y)}
+		assert.Equal(t, exactOrient(p[0], p[1], q), sign(orient2(p[0], p[1], q)))
+	}
+}
+
+func TestIncircle_cocircularGrid(t *testing.T) {
+	x, y := math.Ldexp(1, 40), -math.Ldexp(1, 38)
+	a, b, c := Point{X: x, Y: y}, Point{X: x + 1, Y: y}, Point{X: x + 1, Y: y + 1}
+	d := Point{X: x, Y: y + 1}
+	assert.Zero(t, incircle(a, b, c, d))
+	assert.False(t, inCircumcircle(a, b, c, d))
+	above := Point{X: x, Y: math.Nextafter(y+1, math.Inf(1))}
+	assert.Less(t, incircle(a, b, c, above), 0.0)
+	right := Point{X: math.Nextafter(x, math.Inf(1)), Y: y + 1}
+	assert.Greater(t, incircle(a, b, c, right), 0.0)
+	assert.True(t, inCircumcircle(c, b, a, right))
+}
+
+func TestLine_Intersects_touching(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	l := Line{P: Point{X: 0.1, Y: 0.1}, Q: Point{X: 0.7, Y: 0.7}}
+	for i := 0; i < 1000; i++ {
+		v := 0.1 + 0.6*rng.Float64()
+		// a segment starting exactly on l touches it
+		assert.True(t, l.Intersects(Line{P: Point{X: v, Y: v}, Q: Point{X: v, Y: v + 1}}))
+		assert.True(t, Line{P: Point{X: v, Y: v - 1}, Q: Point{X: v, Y: v}}.Intersects(l))
+		// one starting a unit in the last place above it doesn't
+		above := Point{X: v, Y: math.Nextafter(v, 1)}
+		assert.False(t, l.Intersects(Line{P: above, Q: Point{X: v, Y: v + 1}}))
+	}
+	assert.False(t, l.Intersects(Line{P: Point{X: 0.2, Y: 0.2}, Q: Point{X: 0.9, Y: 0.9}}))
+	assert.False(t, l.Intersects(Line{P: Point{X: 0, Y: 1}, Q: Point{X: 1, Y: 2}}))
+	assert.True(t, l.Intersects(Line{P: Point{X: 0, Y: 1}, Q: Point{X: 1, Y: 0}}))
+}
+
+// assertValidTriangulation checks that triangles tile the convex hull of the sites
+// without overlapping, and are Delaunay
+func assertValidTriangulation(t *testing.T, sites []Point, tris []Triangle) {
+	t.Helper()
+	sites = dedupeSitesDelaunay(sites)
+	hull := ConvexHull(sites)
+	var area float64
+	for _, tri := range tris {
+		require.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+		area += orient2(tri.A, tri.B, tri.C) / 2
+		for _, p := range sites {
+			require.False(t, inCircumcircle(tri.A, tri.B, tri.C, p), "%v in %v", p, tri)
+		}
+	}
+	// fan the hull from a corner: the shoelace formula loses too much far from the
+	// origin
+	var hullArea float64
+	for i := 1; i+1 < len(hull.Points); i++ {
+		hullArea += orient2(hull.Points[0], hull.Points[i], hull.Points[i+1]) / 2
+	}
+	assert.InDelta(t, hullArea, area, 1e-9*(1+hullArea))
+	// with h sites on the hull boundary, including those in the middle of its
+	// edges, a triangulation of n sites has 2n-2-h triangles
+	onHull := 0
+	for _, p := range sites {
+		for i := range hull.Points {
+			a, b := hull.Points[i], hull.Points[(i+1)%len(hull.Points)]
+			if orient2(a, b, p) == 0 && Vec2FromPoints(a, p).Dot(Vec2FromPoints(b, p)) <= 0 {
+				onHull++
+				break
+			}
+		}
+	}
+	assert.Len(t, tris, 2*len(sites)-2-onHull)
+}
+
+func TestDelaunayTriangles_exactPredicates(t *testing.T) {
+	padua := PaduaPoints(20)
+	var polygon []Point
+	for i := 0; i < 64; i++ {
+		a := float64(i) * Tau / 64
+		polygon = append(polygon, Point{X: 3 + 2*math.Cos(a), Y: 1 + 2*math.Sin(a)})
+	}
+	polygon = append(polygon, Point{X: 3, Y: 1})
+	var grid []Point
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 12; j++ {
+			grid = append(grid, Point{X: 1e6 + 0.1*float64(i), Y: -1e6 + 0.1*float64(j)})
+		}
+	}
+	for _, sites := range [][]Point{padua, polygon, grid} {
+		for _, m := range []DelaunayMethod{DelaunaySweepHull, DelaunayBowyerWatson} {
+			tris := DelaunayTrianglesWith(sites, m)
+			if m == DelaunaySweepHull {
+				assertValidTriangulation(t, sites, tris)
+			}
+			for _, tri := range tris {
+				assert.Greater(t, orient2(tri.A, tri.B, tri.C), 0.0)
+			}
+		}
+	}
+}
+
+func TestVoronoiWithRect_nearlyCocircular(t *testing.T) {
+	// sites a tenth apart aren't exactly cocircular, so the cells may meet at
+	// vertices joined by tiny edges, but they still tile the rectangle
+	b := Rect{X: 0, Y: 0, W: 1.2, H: 1.2}
+	var sites []Point
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 12; j++ {
+			sites = append(sites, Point{X: 0.05 + 0.1*float64(i), Y: 0.05 + 0.1*float64(j)})
+		}
+	}
+	sites = append(sites, PaduaPoints(8)...)
+	for i := range sites[144:] {
+		sites[144+i] = Point{X: 0.6 + 0.5*sites[144+i].X, Y: 0.6 + 0.5*sites[144+i].Y}
+	}
+	curves, err := VoronoiWithRect(b, sites)
+	require.NoError(t, err)
+	require.Len(t, curves, len(sites))
+	var sum float64
+	for i, c := range curves {
+		sum += c.Area()
+		assert.GreaterOrEqual(t, c.SignedArea(), 0.0)
+		assert.True(t, c.ContainsPoint(sites[i], NonZero) || c.PointOnEdge(sites[i], 1e-9), "site %v", sites[i])
+	}
+	assert.InDelta(t, b.W*b.H, sum, 1e-9)
+}
@@ -1,7 +1,9 @@
 package gaul
 
 import (
+	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/peterhellberg/gfx"
@@ -145,6 +147,40 @@ func (r *Rng) UniformRandomPoints(num int, rect Rect) []Point {
 	return points
 }
 
+// UniformRandomPointsInTriangles generates a list of points uniformly distributed over
+// the area covered by a set of non-overlapping triangles, such as the triangulation of
+// a polygon. Triangles are picked with probability proportional to their area. The
+// points only depend on the seed of the generator.
+func (r *Rng) UniformRandomPointsInTriangles(num int, tris []Triangle) []Point {
+	cum := make([]float64, len(tris))
+	total := 0.0
+	for i, t := range tris {
+		total += math.Abs(orient2(t.A, t.B, t.C)) / 2
+		cum[i] = total
+	}
+	if num <= 0 || total == 0 {
+		return nil
+	}
+	points := make([]Point, num)
+	for i := range points {
+		k := sort.SearchFloat64s(cum, r.Prng.Float64()*total)
+		if k == len(tris) {
+			k--
+		}
+		t := tris[k]
+		// reflect samples from the far half of the parallelogram back into the triangle
+		u, v := r.Prng.Float64(), r.Prng.Float64()
+		if u+v > 1 {
+			u, v = 1-u, 1-v
+		}
+		points[i] = Point{
+			X: t.A.X + u*(t.B.X-t.A.X) + v*(t.C.X-t.A.X),
+			Y: t.A.Y + u*(t.B.Y-t.A.Y) + v*(t.C.Y-t.A.Y),
+		}
+	}
+	return points
+}
+
 func (r *Rng) NoisyRandomPoints(num int, threshold float64, rect Rect) []Point {
 	var points []Point
 	maxtries := 10 * num
@@ -0,0 +1,332 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// maxRefineAngle caps RefineOptions.MinAngle; much above it refinement may never finish
+const maxRefineAngle = 33 * Pi / 180
+
+// RefineOptions configures [RefineTriangles]
+type RefineOptions struct {
+	// MinAngle is the smallest angle, in radians, wanted in every triangle. It's capped
+	// at 33 degrees, above which refinement may not finish. Angles smaller than this
+	// between two input segments can't be improved and are left alone.
+	MinAngle float64
+	// MaxArea is the largest area allowed for a triangle, 0 for no limit
+	MaxArea float64
+	// AreaField, if set, gives the largest area allowed for a triangle around a point,
+	// evaluated at the triangle's centroid, for meshes whose density varies. It applies
+	// together with MaxArea; non-positive values mean no limit.
+	AreaField func(p Point) float64
+	// MaxPoints limits the number of points added. Defaults to 100000.
+	MaxPoints int
+	// Rule picks the triangles inside the boundary curves, which are the ones
+	// refined and returned
+	Rule FillRule
+}
+
+func (o RefineOptions) maxPoints() int {
+	if o.MaxPoints <= 0 {
+		return 100000
+	}
+	return o.MaxPoints
+}
+
+// RefineTriangles triangulates the sites, constraints and boundary like
+// [ConstrainedDelaunayTriangles] and then adds points until every triangle of the
+// domain has no angle below opts.MinAngle and no more than the allowed area. It returns
+// the counterclockwise triangles and the points they use, the input points first.
+//
+// Points are added as in Ruppert's algorithm: a segment (a constraint, a boundary
+// edge, or the convex hull without a boundary) whose diametral circle holds a vertex
+// is split, and a bad triangle is split at its circumcenter, unless that would encroach
+// a segment, which is then split instead. Segments are split at their middle, or next
+// to an input vertex at a power of two from it so that splits on segments meeting at
+// small angles line up on concentric circles and don't cascade.
+func RefineTriangles(sites []Point, constraints []Line, boundary []Curve, opts RefineOptions) ([]Triangle, []Point) {
+	tr, keep := constrainedTriangulation(sites, constraints, boundary, opts.Rule)
+	if tr == nil {
+		return nil, nil
+	}
+	r := &refiner{
+		tr:        tr,
+		keep:      keep,
+		input:     len(tr.pts),
+		maxArea:   opts.MaxArea,
+		areaField: opts.AreaField,
+		budget:    opts.maxPoints(),
+	}
+	if opts.MinAngle > 0 {
+		r.sinAngle = math.Sin(math.Min(opts.MinAngle, maxRefineAngle))
+	}
+	lo, hi := tr.pts[3], tr.pts[3]
+	for _, p := range tr.pts[3:] {
+		lo = Point{X: math.Min(lo.X, p.X), Y: math.Min(lo.Y, p.Y)}
+		hi = Point{X: math.Max(hi.X, p.X), Y: math.Max(hi.Y, p.Y)}
+	}
+	r.minLength = 1e-9 * Distance(lo, hi)
+	r.run()
+
+	var tris []Triangle
+	used := make(map[int]bool)
+	for t, v := range tr.tris {
+		if r.inDomain(t) {
+			tris = append(tris, tr.triangle(t))
+			used[v[0]], used[v[1]], used[v[2]] = true, true, true
+		}
+	}
+	idx := make([]int, 0, len(used))
+	for v := range used {
+		idx = append(idx, v)
+	}
+	sort.Ints(idx)
+	pts := make([]Point, len(idx))
+	for i, v := range idx {
+		pts[i] = tr.pts[v]
+	}
+	return tris, pts
+}
+
+// refiner holds the state of Delaunay refinement
+type refiner struct {
+	tr        *triangulation
+	keep      func(Triangle) bool
+	input     int     // number of vertices before refinement
+	sinAngle  float64 // sine of the minimum angle, 0 for none
+	maxArea   float64
+	areaField func(Point) float64
+	minLength float64 // triangles with shorter edges are left alone
+	budget    int     // points that may still be added
+}
+
+func (r *refiner) run() {
+	tr := r.tr
+	var segments [][2]int
+	for t := range tr.tris {
+		for k := 0; k < 3; k++ {
+			if tr.fixed[t][k] {
+				segments = append(segments, [2]int{tr.tris[t][k], tr.tris[t][(k+1)%3]})
+			}
+		}
+	}
+	r.splitEncroached(segments)
+	for changed := true; changed && r.budget > 0; {
+		changed = false
+		// triangles added during a pass are visited in the same pass
+		for t := 0; t < len(tr.tris) && r.budget > 0; t++ {
+			if r.inDomain(t) && r.bad(t) && r.split(t) {
+				changed = true
+			}
+		}
+	}
+}
+
+func (r *refiner) inDomain(t int) bool {
+	v := r.tr.tris[t]
+	if v[0] < 3 || v[1] < 3 || v[2] < 3 {
+		return false
+	}
+	return r.keep == nil || r.keep(r.tr.triangle(t))
+}
+
+// bad reports whether triangle t is too large or has too small an angle
+func (r *refiner) bad(t int) bool {
+	tr := r.tr
+	v := tr.tris[t]
+	var l2 [3]float64 // squared length of edge k
+	short := 0
+	for k := 0; k < 3; k++ {
+		l2[k] = SquaredDistance(tr.pts[v[k]], tr.pts[v[(k+1)%3]])
+		if l2[k] < l2[short] {
+			short = k
+		}
+	}
+	if math.Sqrt(l2[short]) < r.minLength {
+		return false
+	}
+	tri := tr.triangle(t)
+	area := orient2(tri.A, tri.B, tri.C) / 2
+	limit := r.maxArea
+	if r.areaField != nil {
+		if a := r.areaField(tri.Centroid()); a > 0 && (limit <= 0 || a < limit) {
+			limit = a
+		}
+	}
+	if limit > 0 && area > limit {
+		return true
+	}
+	if r.sinAngle == 0 {
+		return false
+	}
+	// the smallest angle is opposite the shortest edge; between two segments it stays
+	k1, k2 := (short+1)%3, (short+2)%3
+	if tr.fixed[t][k1] && tr.fixed[t][k2] {
+		return false
+	}
+	return 2*area/math.Sqrt(l2[k1]*l2[k2]) < r.sinAngle
+}
+
+// split inserts the circumcenter of triangle t, or splits the segments it would
+// encroach, and reports whether a point was added
+func (r *refiner) split(t int) bool {
+	tr := r.tr
+	tri := tr.triangle(t)
+	c := circleThrough(tri.A, tri.B, tri.C).Center
+	s, k, ok := r.walk(t, c)
+	if !ok {
+		if k >= 0 && tr.fixed[s][k] {
+			// a segment hides the circumcenter from the triangle
+			r.forceSplit([][2]int{{tr.tris[s][k], tr.tris[s][(k+1)%3]}})
+			return true
+		}
+		return false
+	}
+	kind, k := tr.classify(s, c)
+	if kind == locOutside || kind == locVertex || !r.inDomain(s) {
+		return false
+	}
+	// the segments bounding the triangles the new vertex would replace are the ones
+	// it would see
+	var enc [][2]int
+	seen := map[int]bool{s: true}
+	stack := []int{s}
+	for len(stack) > 0 {
+		x := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for j := 0; j < 3; j++ {
+			a, b := tr.tris[x][j], tr.tris[x][(j+1)%3]
+			if tr.fixed[x][j] {
+				if encroaches(tr.pts[a], tr.pts[b], c) {
+					enc = append(enc, [2]int{a, b})
+				}
+				continue
+			}
+			u := tr.adj[x][j]
+			if u < 0 || seen[u] {
+				continue
+			}
+			if v := tr.tris[u]; inCircumcircle(tr.pts[v[0]], tr.pts[v[1]], tr.pts[v[2]], c) {
+				seen[u] = true
+				stack = append(stack, u)
+			}
+		}
+	}
+	if len(enc) > 0 {
+		r.forceSplit(enc)
+		return true
+	}
+	tr.insertAt(c, s, kind, k)
+	r.budget--
+	return true
+}
+
+// walk follows the straight line from the centroid of triangle t to p. It returns the
+// triangle holding p, or the triangle and edge where a segment or the outside of the
+// triangulation stops the walk.
+func (r *refiner) walk(t int, p Point) (int, int, bool) {
+	tr := r.tr
+	from := tr.triangle(t).Centroid()
+	for steps := 0; steps < len(tr.tris); steps++ {
+		v := tr.tris[t]
+		exit := -1
+		for k := 0; k < 3 && exit < 0; k++ {
+			a, b := tr.pts[v[k]], tr.pts[v[(k+1)%3]]
+			if orient2(a, b, p) < 0 && orient2(from, p, a) <= 0 && orient2(from, p, b) >= 0 {
+				exit = k
+			}
+		}
+		if exit < 0 {
+			return t, -1, true
+		}
+		if tr.fixed[t][exit] || tr.adj[t][exit] < 0 {
+			return t, exit, false
+		}
+		t = tr.adj[t][exit]
+	}
+	return t, -1, false
+}
+
+// encroaches reports whether p lies inside the diametral circle of segment a-b
+func encroaches(a, b, p Point) bool {
+	return Vec2FromPoints(p, a).Dot(Vec2FromPoints(p, b)) < 0
+}
+
+// encroached reports whether a vertex of the domain next to segment k of triangle t
+// lies inside its diametral circle
+func (r *refiner) encroached(t, k int) bool {
+	tr := r.tr
+	a, b := tr.tris[t][k], tr.tris[t][(k+1)%3]
+	for _, s := range []int{t, tr.adj[t][k]} {
+		if s < 0 || !r.inDomain(s) {
+			continue
+		}
+		for _, w := range tr.tris[s] {
+			if w != a && w != b && encroaches(tr.pts[a], tr.pts[b], tr.pts[w]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forceSplit splits the given segments whether or not they are encroached, then
+// splits any segments encroached as a result
+func (r *refiner) forceSplit(segments [][2]int) {
+	var next [][2]int
+	for _, seg := range segments {
+		if r.budget <= 0 {
+			return
+		}
+		if t, k, ok := r.tr.findEdge(seg[0], seg[1]); ok && r.tr.fixed[t][k] {
+			next = append(next, r.splitSegment(t, k)...)
+		}
+	}
+	r.splitEncroached(next)
+}
+
+// splitEncroached splits encroached segments, and the pieces of them that are still
+// encroached, until none of the given segments is
+func (r *refiner) splitEncroached(segments [][2]int) {
+	for len(segments) > 0 && r.budget > 0 {
+		seg := segments[len(segments)-1]
+		segments = segments[:len(segments)-1]
+		t, k, ok := r.tr.findEdge(seg[0], seg[1])
+		if !ok || !r.tr.fixed[t][k] || !r.encroached(t, k) {
+			continue
+		}
+		segments = append(segments, r.splitSegment(t, k)...)
+	}
+}
+
+// splitSegment splits segment k of triangle t and returns the segments to check
+// afterwards: its two halves and those seen by the new vertex
+func (r *refiner) splitSegment(t, k int) [][2]int {
+	tr := r.tr
+	a, b := tr.tris[t][k], tr.tris[t][(k+1)%3]
+	pa, pb := tr.pts[a], tr.pts[b]
+	f := 0.5
+	if (a < r.input) != (b < r.input) {
+		// split at a power of two from the input vertex (concentric shells)
+		d := Distance(pa, pb)
+		f = math.Exp2(math.Round(math.Log2(d/2))) / d
+		if b < r.input {
+			f = 1 - f
+		}
+	}
+	v := len(tr.pts)
+	tr.pts = append(tr.pts, pa.Lerp(pb, f))
+	tr.vertTri = append(tr.vertTri, t)
+	tr.splitEdge(t, k, v)
+	r.budget--
+	out := [][2]int{{a, v}, {v, b}}
+	for _, s := range tr.around(v) {
+		for j := 0; j < 3; j++ {
+			if tr.fixed[s][j] {
+				out = append(out, [2]int{tr.tris[s][j], tr.tris[s][(j+1)%3]})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package gaul
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// minTriangleAngle returns the smallest angle of a triangle
+func minTriangleAngle(t Triangle) float64 {
+	angle := func(p, q, r Point) float64 {
+		u, v := Vec2FromPoints(p, q), Vec2FromPoints(p, r)
+		return math.Acos(Clamp(-1, 1, u.Dot(v)/(u.Mag()*v.Mag())))
+	}
+	return math.Min(angle(t.A, t.B, t.C), math.Min(angle(t.B, t.C, t.A), angle(t.C, t.A, t.B)))
+}
+
+func TestRefineTriangles_square(t *testing.T) {
+	square := Curve{Points: []Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}}, Closed: true}
+	minAngle := 28 * Pi / 180
+	tris, pts := RefineTriangles(nil, nil, []Curve{square}, RefineOptions{MinAngle: minAngle, MaxArea: 0.01})
+	require.NotEmpty(t, tris)
+	assert.InDelta(t, 1, trianglesArea(tris), 1e-9)
+	for _, tri := range tris {
+		assert.GreaterOrEqual(t, minTriangleAngle(tri), minAngle-1e-9)
+		assert.LessOrEqual(t, orient2(tri.A, tri.B, tri.C)/2, 0.01+1e-12)
+	}
+	assert.Equal(t, square.Points, pts[:4])
+	// every point is a vertex and no vertex is repeated
+	seen := make(map[Point]bool)
+	for _, p := range pts {
+		assert.False(t, seen[p])
+		seen[p] = true
+	}
+	for _, tri := range tris {
+		assert.True(t, seen[tri.A] && seen[tri.B] && seen[tri.C])
+	}
+}
+
+func TestRefineTriangles_holeAndConstraint(t *testing.T) {
+	outer := Circle{Center: Point{X: 0, Y: 0}, Radius: 10}.ToCurve(48)
+	hole := Rect{X: -3, Y: -2, W: 6, H: 4}.ToCurve()
+	feature := Line{P: Point{X: -8, Y: 5}, Q: Point{X: 7, Y: 6}}
+	region := []Curve{outer, hole}
+	minAngle := 25 * Pi / 180
+	tris, _ := RefineTriangles(nil, []Line{feature}, region, RefineOptions{MinAngle: minAngle, MaxArea: 2, Rule: EvenOdd})
+	require.NotEmpty(t, tris)
+	assert.InDelta(t, outer.Area()-hole.Area(), trianglesArea(tris), 1e-6)
+	for _, tri := range tris {
+		assert.GreaterOrEqual(t, minTriangleAngle(tri), minAngle-1e-9)
+		assert.False(t, hole.ContainsPoint(tri.Centroid(), NonZero))
+	}
+	assertCoversSegment(t, tris, feature)
+}
+
+func TestRefineTriangles_areaField(t *testing.T) {
+	rect := Rect{X: 0, Y: 0, W: 10, H: 5}.ToCurve()
+	field := func(p Point) float64 {
+		if p.X < 5 {
+			return 0.05
+		}
+		return 1
+	}
+	tris, _ := RefineTriangles(nil, nil, []Curve{rect}, RefineOptions{MinAngle: 20 * Pi / 180, AreaField: field})
+	left, right := 0, 0
+	for _, tri := range tris {
+		c := tri.Centroid()
+		assert.LessOrEqual(t, orient2(tri.A, tri.B, tri.C)/2, field(c)+1e-12)
+		if c.X < 5 {
+			left++
+		} else {
+			right++
+		}
+	}
+	assert.Greater(t, left, 4*right)
+	assert.InDelta(t, 50, trianglesArea(tris), 1e-9)
+}
+
+func TestRefineTriangles_smallInputAngle(t *testing.T) {
+	// a thin wedge can't be meshed without small angles at its tip, but refinement
+	// still finishes well within its budget
+	wedge := Curve{Points: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 1}}, Closed: true}
+	tris, pts := RefineTriangles(nil, nil, []Curve{wedge}, RefineOptions{MinAngle: 30 * Pi / 180, MaxPoints: 5000})
+	assert.Less(t, len(pts), 1000)
+	assert.InDelta(t, 5, trianglesArea(tris), 1e-9)
+}
+
+func TestRefineTriangles_sites(t *testing.T) {
+	sites := []Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 3}, {X: 0, Y: 3}, {X: 1, Y: 1}}
+	tris, pts := RefineTriangles(sites, nil, nil, RefineOptions{MaxArea: 0.5})
+	assert.InDelta(t, 12, trianglesArea(tris), 1e-9)
+	assert.Equal(t, sites, pts[:len(sites)])
+	for _, tri := range tris {
+		assert.LessOrEqual(t, orient2(tri.A, tri.B, tri.C)/2, 0.5+1e-12)
+	}
+	tris, pts = RefineTriangles(sites[:2], nil, nil, RefineOptions{MaxArea: 0.5})
+	assert.Empty(t, tris)
+	assert.Empty(t, pts)
+}
@@ -0,0 +1,387 @@
+package gaul
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SimplifyRDP returns a copy of the curve with vertices removed using the
+// Ramer–Douglas–Peucker algorithm: every removed vertex lies within tolerance of the
+// simplified curve. End points of open curves are always kept, and closed curves keep
+// at least three vertices.
+//
+// When keepSimple is set, segments of the result that cross each other are refined
+// with more of the original vertices until the crossings are gone, so a curve that
+// doesn't intersect itself stays that way.
+func (c *Curve) SimplifyRDP(tolerance float64, keepSimple bool) Curve {
+	pts := simplifyInput(c)
+	n := len(pts)
+	if n < 3 || (c.Closed && n < 4) {
+		return Curve{Points: pts, Closed: c.Closed}
+	}
+	keep := make([]bool, n)
+	if c.Closed {
+		// split the loop at the vertex farthest from the first one
+		far := 0
+		for i := 1; i < n; i++ {
+			if SquaredDistance(pts[0], pts[i]) > SquaredDistance(pts[0], pts[far]) {
+				far = i
+			}
+		}
+		keep[0], keep[far] = true, true
+		loop := append(append([]Point(nil), pts...), pts[0])
+		keepLoop := make([]bool, n+1)
+		rdpMark(loop, 0, far, tolerance, keepLoop)
+		rdpMark(loop, far, n, tolerance, keepLoop)
+		for i := 1; i < n; i++ {
+			keep[i] = keep[i] || keepLoop[i]
+		}
+		if keptCount(keep) < 3 {
+			// a sliver: keep the vertex farthest from the chord as well
+			k, _ := rdpFarthest(loop, 0, far)
+			keep[k] = true
+		}
+	} else {
+		keep[0], keep[n-1] = true, true
+		rdpMark(pts, 0, n-1, tolerance, keep)
+	}
+	if keepSimple {
+		for refineCrossings(pts, keep, c.Closed) {
+		}
+	}
+	return Curve{Points: keptPoints(pts, keep), Closed: c.Closed}
+}
+
+// rdpMark marks the vertices strictly between i and j that the simplification keeps
+func rdpMark(pts []Point, i, j int, tolerance float64, keep []bool) {
+	if j-i < 2 {
+		return
+	}
+	k, d := rdpFarthest(pts, i, j)
+	if d <= tolerance {
+		return
+	}
+	keep[k] = true
+	rdpMark(pts, i, k, tolerance, keep)
+	rdpMark(pts, k, j, tolerance, keep)
+}
+
+// rdpFarthest returns the vertex strictly between i and j farthest from segment i-j,
+// and its distance
+func rdpFarthest(pts []Point, i, j int) (int, float64) {
+	chord := Line{P: pts[i], Q: pts[j]}
+	best, bestD := i+1, -1.0
+	for k := i + 1; k < j; k++ {
+		var d float64
+		if chord.P.IsEqual(chord.Q) {
+			d = Distance(chord.P, pts[k])
+		} else {
+			d = chord.SDF(pts[k])
+		}
+		if d > bestD {
+			best, bestD = k, d
+		}
+	}
+	return best, bestD
+}
+
+// refineCrossings keeps one more vertex inside every simplified segment that crosses
+// another one, and reports whether anything changed
+func refineCrossings(pts []Point, keep []bool, closed bool) bool {
+	var kept []int
+	for i, k := range keep {
+		if k {
+			kept = append(kept, i)
+		}
+	}
+	simplified := Curve{Points: make([]Point, len(kept)), Closed: closed}
+	for i, k := range kept {
+		simplified.Points[i] = pts[k]
+	}
+	changed := false
+	split := func(seg int) {
+		from := kept[seg]
+		to := len(pts)
+		if seg+1 < len(kept) {
+			to = kept[seg+1]
+		}
+		if to-from < 2 {
+			return
+		}
+		loop := pts
+		if to == len(pts) {
+			loop = append(append([]Point(nil), pts...), pts[0])
+		}
+		k, _ := rdpFarthest(loop, from, to)
+		keep[k] = true
+		changed = true
+	}
+	for _, x := range CurveIntersections([]Curve{simplified}) {
+		split(x.SegmentA)
+		split(x.SegmentB)
+	}
+	return changed
+}
+
+// SimplifyVisvalingam returns a copy of the curve with vertices removed using the
+// Visvalingam–Whyatt algorithm: vertices are removed in order of the area of the
+// triangle they form with their neighbours, as long as that area is below minArea.
+// End points of open curves are always kept, and closed curves keep at least three
+// vertices.
+//
+// When keepSimple is set, a vertex is only removed if no other vertex lies in the
+// triangle it forms with its neighbours, so a curve that doesn't intersect itself
+// stays that way.
+func (c *Curve) SimplifyVisvalingam(minArea float64, keepSimple bool) Curve {
+	return visvalingam(c, minArea, 0, keepSimple)
+}
+
+// SimplifyToCount returns a copy of the curve reduced to at most n vertices with the
+// Visvalingam–Whyatt algorithm (see [Curve.SimplifyVisvalingam]). Fewer vertices than
+// requested may remain when keepSimple prevents further removals, and open curves keep
+// at least their two end points.
+func (c *Curve) SimplifyToCount(n int, keepSimple bool) Curve {
+	return visvalingam(c, math.Inf(1), n, keepSimple)
+}
+
+// vwEntry is a candidate vertex for removal; stale entries are skipped when popped
+type vwEntry struct {
+	index   int
+	area    float64
+	version int
+}
+
+type vwQueue []vwEntry
+
+func (q vwQueue) Len() int            { return len(q) }
+func (q vwQueue) Less(i, j int) bool  { return q[i].area < q[j].area }
+func (q vwQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *vwQueue) Push(x interface{}) { *q = append(*q, x.(vwEntry)) }
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+func visvalingam(c *Curve, maxArea float64, target int, keepSimple bool) Curve {
+	pts := simplifyInput(c)
+	n := len(pts)
+	minKeep := 2
+	if c.Closed {
+		minKeep = 3
+	}
+	if target < minKeep {
+		target = minKeep
+	}
+	if n <= target {
+		return Curve{Points: pts, Closed: c.Closed}
+	}
+	prev := make([]int, n)
+	next := make([]int, n)
+	for i := range pts {
+		prev[i], next[i] = i-1, i+1
+	}
+	if c.Closed {
+		prev[0], next[n-1] = n-1, 0
+	}
+	removable := func(i int) bool {
+		return prev[i] >= 0 && next[i] < n
+	}
+	area := func(i int) float64 {
+		return math.Abs(orient2(pts[prev[i]], pts[i], pts[next[i]])) / 2
+	}
+
+	var grid *pointGrid
+	if keepSimple {
+		grid = newPointGrid(pts)
+	}
+	blocked := func(i int) bool {
+		if grid == nil {
+			return false
+		}
+		a, b, cc := prev[i], i, next[i]
+		tri := Triangle{A: pts[a], B: pts[b], C: pts[cc]}
+		found := false
+		grid.query(tri.Boundary(), func(k int) bool {
+			if k == a || k == b || k == cc || pts[k].IsEqual(pts[a]) || pts[k].IsEqual(pts[cc]) {
+				return true
+			}
+			if triangleContainsOrTouches(tri, pts[k]) {
+				found = true
+				return false
+			}
+			return true
+		})
+		return found
+	}
+
+	version := make([]int, n)
+	removed := make([]bool, n)
+	q := &vwQueue{}
+	for i := range pts {
+		if removable(i) {
+			heap.Push(q, vwEntry{index: i, area: area(i)})
+		}
+	}
+	remaining := n
+	var last float64
+	for q.Len() > 0 && remaining > target {
+		e := heap.Pop(q).(vwEntry)
+		if removed[e.index] || e.version != version[e.index] {
+			continue
+		}
+		// the effective area never drops below that of an earlier removal, so
+		// removals happen in a consistent order
+		a := math.Max(e.area, last)
+		if a >= maxArea {
+			break
+		}
+		if blocked(e.index) {
+			// it is queued again if one of its neighbours goes
+			continue
+		}
+		last = a
+		i := e.index
+		removed[i] = true
+		remaining--
+		if grid != nil {
+			grid.remove(i, pts[i])
+		}
+		p, nx := prev[i], next[i]
+		if p >= 0 {
+			next[p] = nx
+		}
+		if nx < n {
+			prev[nx] = p
+		}
+		for _, k := range []int{p, nx} {
+			if k >= 0 && k < n && removable(k) {
+				version[k]++
+				heap.Push(q, vwEntry{index: k, area: area(k), version: version[k]})
+			}
+		}
+	}
+	keep := make([]bool, n)
+	for i := range keep {
+		keep[i] = !removed[i]
+	}
+	return Curve{Points: keptPoints(pts, keep), Closed: c.Closed}
+}
+
+// triangleContainsOrTouches is an orientation-independent closed triangle test
+func triangleContainsOrTouches(t Triangle, p Point) bool {
+	d1 := orient2(t.A, t.B, p)
+	d2 := orient2(t.B, t.C, p)
+	d3 := orient2(t.C, t.A, p)
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
+
+// simplifyInput copies the points of a curve without consecutive duplicates, or a
+// repeated first point at the end of a closed curve
+func simplifyInput(c *Curve) []Point {
+	pts := dedupeConsecutivePoints(c.Points)
+	if c.Closed && len(pts) > 1 && pts[0].IsEqual(pts[len(pts)-1]) {
+		pts = pts[:len(pts)-1]
+	}
+	return pts
+}
+
+func keptPoints(pts []Point, keep []bool) []Point {
+	out := make([]Point, 0, len(pts))
+	for i, k := range keep {
+		if k {
+			out = append(out, pts[i])
+		}
+	}
+	return out
+}
+
+func keptCount(flags []bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+// pointGrid is a uniform grid over a fixed set of points that supports removal
+type pointGrid struct {
+	minX, minY float64
+	size       float64
+	nx, ny     int // cells along each axis
+	cells      map[[2]int][]int
+}
+
+// newPointGrid sizes the cells to hold about one point each, but no smaller than
+// the longer side over n, so that collinear or axis-aligned points, whose bounding
+// box has no area, get at most about n cells per axis
+func newPointGrid(pts []Point) *pointGrid {
+	r := (&Curve{Points: pts}).Boundary()
+	n := float64(len(pts))
+	size := math.Max(math.Sqrt(r.W*r.H/n), math.Max(r.W, r.H)/n)
+	if size == 0 || math.IsNaN(size) {
+		size = 1
+	}
+	g := &pointGrid{minX: r.X, minY: r.Y, size: size, cells: make(map[[2]int][]int)}
+	last := g.cell(Point{X: r.X + r.W, Y: r.Y + r.H})
+	g.nx, g.ny = last[0]+1, last[1]+1
+	for i, p := range pts {
+		k := g.cell(p)
+		g.cells[k] = append(g.cells[k], i)
+	}
+	return g
+}
+
+func (g *pointGrid) cell(p Point) [2]int {
+	return [2]int{int(math.Floor((p.X - g.minX) / g.size)), int(math.Floor((p.Y - g.minY) / g.size))}
+}
+
+func (g *pointGrid) remove(i int, p Point) {
+	k := g.cell(p)
+	list := g.cells[k]
+	for j, v := range list {
+		if v == i {
+			g.cells[k] = append(list[:j], list[j+1:]...)
+			return
+		}
+	}
+}
+
+// query calls fn for the points in the cells overlapping r until fn returns false.
+// It walks no more cells than the grid has, nor than hold points.
+func (g *pointGrid) query(r Rect, fn func(i int) bool) {
+	lo := g.cell(Point{X: r.X, Y: r.Y})
+	hi := g.cell(Point{X: r.X + r.W, Y: r.Y + r.H})
+	lo[0], lo[1] = max(lo[0], 0), max(lo[1], 0)
+	hi[0], hi[1] = min(hi[0], g.nx-1), min(hi[1], g.ny-1)
+	if hi[0] < lo[0] || hi[1] < lo[1] {
+		return
+	}
+	if (hi[0]-lo[0]+1)*(hi[1]-lo[1]+1) > len(g.cells) {
+		for k, list := range g.cells {
+			if k[0] < lo[0] || k[0] > hi[0] || k[1] < lo[1] || k[1] > hi[1] {
+				continue
+			}
+			for _, i := range list {
+				if !fn(i) {
+					return
+				}
+			}
+		}
+		return
+	}
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for _, i := range g.cells[[2]int{x, y}] {
+				if !fn(i) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package gaul
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sineCurve(n int) Curve {
+	var c Curve
+	for i := 0; i < n; i++ {
+		x := Tau * float64(i) / float64(n-1)
+		c.AddPoint(x, math.Sin(x))
+	}
+	return c
+}
+
+func polylineDistance(c Curve, p Point) float64 {
+	d := math.Inf(1)
+	n := len(c.Points)
+	count := n - 1
+	if c.Closed {
+		count = n
+	}
+	for i := 0; i < count; i++ {
+		d = math.Min(d, Line{P: c.Points[i], Q: c.Points[(i+1)%n]}.SDF(p))
+	}
+	return d
+}
+
+func TestCurve_SimplifyRDP(t *testing.T) {
+	c := sineCurve(1000)
+	got := c.SimplifyRDP(0.01, false)
+	assert.False(t, got.Closed)
+	assert.Less(t, len(got.Points), 50)
+	assert.Equal(t, c.Points[0], got.Points[0])
+	assert.Equal(t, c.Points[999], got.Points[len(got.Points)-1])
+	for _, p := range c.Points {
+		assert.LessOrEqual(t, polylineDistance(got, p), 0.01+1e-12)
+	}
+
+	straight := Curve{Points: []Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}}
+	assert.Equal(t, []Point{{0, 0}, {3, 3}}, straight.SimplifyRDP(0, false).Points)
+}
+
+func TestCurve_SimplifyRDP_closed(t *testing.T) {
+	c := Circle{Center: Point{}, Radius: 1}.ToCurve(500)
+	got := c.SimplifyRDP(0.01, false)
+	assert.True(t, got.Closed)
+	assert.Less(t, len(got.Points), 40)
+	for _, p := range c.Points {
+		assert.LessOrEqual(t, polylineDistance(got, p), 0.01+1e-12)
+	}
+	got = c.SimplifyRDP(10, false)
+	assert.Len(t, got.Points, 3)
+}
+
+func TestCurve_SimplifyVisvalingam(t *testing.T) {
+	sq := Curve{Closed: true, Points: []Point{
+		{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1},
+	}}
+	got := sq.SimplifyVisvalingam(1e-9, false)
+	assert.Equal(t, []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, got.Points)
+	assert.True(t, got.Closed)
+
+	c := sineCurve(1000)
+	got = c.SimplifyVisvalingam(1e-4, false)
+	assert.Less(t, len(got.Points), 100)
+	assert.Equal(t, c.Points[0], got.Points[0])
+	assert.Equal(t, c.Points[999], got.Points[len(got.Points)-1])
+
+	got = c.SimplifyToCount(20, false)
+	assert.Len(t, got.Points, 20)
+	got = c.SimplifyToCount(0, false)
+	assert.Len(t, got.Points, 2)
+}
+
+func TestCurve_Simplify_keepSimple(t *testing.T) {
+	// a small dent in the lower edge, with a spike from the upper edge reaching down
+	// between the dent and the straight line that would replace it
+	c := Curve{Points: []Point{
+		{0, 0}, {4, 0}, {5, -0.4}, {6, 0}, {10, 0},
+		{10, 1}, {5.5, 1}, {5, -0.2}, {4.5, 1}, {0, 1},
+	}}
+	require.Empty(t, CurveIntersections([]Curve{c}))
+
+	loose := c.SimplifyRDP(0.5, false)
+	require.NotEmpty(t, CurveIntersections([]Curve{loose}))
+	simple := c.SimplifyRDP(0.5, true)
+	assert.Empty(t, CurveIntersections([]Curve{simple}))
+	assert.Contains(t, simple.Points, Point{X: 5, Y: -0.4})
+
+	loose = c.SimplifyToCount(7, false)
+	require.NotEmpty(t, CurveIntersections([]Curve{loose}))
+	simple = c.SimplifyToCount(7, true)
+	assert.Empty(t, CurveIntersections([]Curve{simple}))
+	assert.Len(t, simple.Points, 7)
+}
+
+func TestCurve_Simplify_keepSimpleCollinear(t *testing.T) {
+	// a bounding box without area used to give a grid of tiny cells to walk
+	c := Curve{}
+	for i := 0; i < 2000; i++ {
+		c.Points = append(c.Points, Point{X: float64(i), Y: 0})
+	}
+	assert.Len(t, c.SimplifyRDP(0.5, true).Points, 2)
+	assert.Len(t, c.SimplifyToCount(2, true).Points, 2)
+	assert.Len(t, c.SimplifyVisvalingam(1, true).Points, 2)
+}
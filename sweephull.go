@@ -0,0 +1,239 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// sweepHull computes the Delaunay triangulation of unique sites by radial sweep, as
+// in Sinclair's S-hull and the Delaunator library. Starting from a seed triangle
+// near the middle of the sites, the points are added in order of their distance from
+// its circumcenter, so each one lies outside the triangulation built so far. A point
+// is joined to the convex hull edges it can see, found through a hash of the hull
+// vertices by angle around the center, and the new triangles are made Delaunay by
+// edge flips. Sorting dominates, so the whole takes O(n log n) time.
+func sweepHull(pts []Point) []Triangle {
+	n := len(pts)
+	// seed: the point closest to the middle of the bounding box, its nearest
+	// neighbour and the point making the smallest circumcircle with both
+	lo, hi := pts[0], pts[0]
+	for _, p := range pts {
+		lo = Point{X: math.Min(lo.X, p.X), Y: math.Min(lo.Y, p.Y)}
+		hi = Point{X: math.Max(hi.X, p.X), Y: math.Max(hi.Y, p.Y)}
+	}
+	mid := Midpoint(lo, hi)
+	closest := func(p Point, skip int) int {
+		best, bestDist := -1, math.Inf(1)
+		for i, q := range pts {
+			if d := SquaredDistance(p, q); i != skip && d < bestDist {
+				best, bestDist = i, d
+			}
+		}
+		return best
+	}
+	i0 := closest(mid, -1)
+	i1 := closest(pts[i0], i0)
+	i2, minRadius := -1, math.Inf(1)
+	for i, p := range pts {
+		if i == i0 || i == i1 || orient2(pts[i0], pts[i1], p) == 0 {
+			continue
+		}
+		if r := circleThrough(pts[i0], pts[i1], p).Radius; r < minRadius {
+			i2, minRadius = i, r
+		}
+	}
+	if i2 < 0 {
+		// all sites are collinear
+		return nil
+	}
+	if orient2(pts[i0], pts[i1], pts[i2]) < 0 {
+		i1, i2 = i2, i1
+	}
+	center := circleThrough(pts[i0], pts[i1], pts[i2]).Center
+
+	ids := make([]int, n)
+	dists := make([]float64, n)
+	for i, p := range pts {
+		ids[i] = i
+		dists[i] = SquaredDistance(center, p)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return dists[ids[a]] < dists[ids[b]]
+	})
+
+	s := &hullSweep{
+		pts:      pts,
+		center:   center,
+		hullNext: make([]int, n),
+		hullPrev: make([]int, n),
+		hullTri:  make([]int, n),
+		hullHash: make([]int, int(math.Ceil(math.Sqrt(float64(n))))),
+	}
+	for i := range s.hullHash {
+		s.hullHash[i] = -1
+	}
+	s.triangles = make([]int, 0, 6*n)
+	s.halfedges = make([]int, 0, 6*n)
+
+	// the hull runs counterclockwise through hullNext; hullTri[v] is the half-edge of
+	// the hull edge leaving v
+	s.hullNext[i0], s.hullNext[i1], s.hullNext[i2] = i1, i2, i0
+	s.hullPrev[i0], s.hullPrev[i1], s.hullPrev[i2] = i2, i0, i1
+	s.addTriangle(i0, i1, i2, -1, -1, -1)
+	s.hullTri[i0], s.hullTri[i1], s.hullTri[i2] = 0, 1, 2
+	for _, v := range []int{i0, i1, i2} {
+		s.hullHash[s.hashKey(pts[v])] = v
+	}
+
+	for _, k := range ids {
+		if k == i0 || k == i1 || k == i2 {
+			continue
+		}
+		p := pts[k]
+		// find an edge of the hull visible from p
+		key := s.hashKey(p)
+		start := 0
+		for j := 0; j < len(s.hullHash); j++ {
+			start = s.hullHash[(key+j)%len(s.hullHash)]
+			if start >= 0 && start != s.hullNext[start] {
+				break
+			}
+		}
+		start = s.hullPrev[start]
+		e := start
+		for !s.visible(p, e, s.hullNext[e]) {
+			e = s.hullNext[e]
+			if e == start {
+				e = -1
+				break
+			}
+		}
+		if e < 0 {
+			// only possible through rounding on the hull
+			continue
+		}
+
+		t := s.addTriangle(e, k, s.hullNext[e], -1, -1, s.hullTri[e])
+		s.hullTri[e], s.hullTri[k] = t, t+1
+		s.legalize(t + 2)
+
+		// walk forward and backward through the hull, adding more triangles
+		next := s.hullNext[e]
+		for q := s.hullNext[next]; s.visible(p, next, q); q = s.hullNext[next] {
+			t = s.addTriangle(next, k, q, s.hullTri[k], -1, s.hullTri[next])
+			s.hullTri[k] = t + 1
+			s.legalize(t + 2)
+			s.hullNext[next] = next // removed from the hull
+			next = q
+		}
+		for q := s.hullPrev[e]; s.visible(p, q, e); q = s.hullPrev[e] {
+			t = s.addTriangle(q, k, e, -1, s.hullTri[e], s.hullTri[q])
+			s.hullTri[q] = t
+			s.legalize(t + 2)
+			s.hullNext[e] = e
+			e = q
+		}
+
+		s.hullPrev[k], s.hullNext[e] = e, k
+		s.hullPrev[next], s.hullNext[k] = k, next
+		s.hullHash[s.hashKey(p)] = k
+		s.hullHash[s.hashKey(pts[e])] = e
+	}
+
+	out := make([]Triangle, len(s.triangles)/3)
+	for t := range out {
+		out[t] = Triangle{A: pts[s.triangles[3*t]], B: pts[s.triangles[3*t+1]], C: pts[s.triangles[3*t+2]]}
+	}
+	return out
+}
+
+// hullSweep is the state of [sweepHull]. Triangle t is made of the half-edges 3t, 3t+1
+// and 3t+2, half-edge h running from triangles[h] to the next corner; halfedges[h] is
+// its twin, or -1 on the hull.
+type hullSweep struct {
+	pts       []Point
+	center    Point
+	triangles []int
+	halfedges []int
+	hullNext  []int
+	hullPrev  []int
+	hullTri   []int
+	hullHash  []int
+}
+
+// hashKey buckets a point by its angle around the center
+func (s *hullSweep) hashKey(p Point) int {
+	dx, dy := p.X-s.center.X, p.Y-s.center.Y
+	// a monotonic stand-in for the angle, from 0 to 1
+	a := dx / (math.Abs(dx) + math.Abs(dy))
+	if dy > 0 {
+		a = 3 - a
+	} else {
+		a = 1 + a
+	}
+	if math.IsNaN(a) {
+		a = 0
+	}
+	return int(math.Floor(a/4*float64(len(s.hullHash)))) % len(s.hullHash)
+}
+
+// visible reports whether p sees the hull edge from a to b
+func (s *hullSweep) visible(p Point, a, b int) bool {
+	return orient2(s.pts[a], s.pts[b], p) < 0
+}
+
+func (s *hullSweep) link(a, b int) {
+	s.halfedges[a] = b
+	if b >= 0 {
+		s.halfedges[b] = a
+	}
+}
+
+// addTriangle adds the counterclockwise triangle (i0, i1, i2) with the twins of its
+// three half-edges and returns its first half-edge
+func (s *hullSweep) addTriangle(i0, i1, i2, a, b, c int) int {
+	t := len(s.triangles)
+	s.triangles = append(s.triangles, i0, i1, i2)
+	s.halfedges = append(s.halfedges, -1, -1, -1)
+	s.link(t, a)
+	s.link(t+1, b)
+	s.link(t+2, c)
+	return t
+}
+
+// legalize flips half-edge a, and the edges exposed by flipping it, until they pass
+// the empty circumcircle test. The edges checked are all opposite the point just
+// added.
+func (s *hullSweep) legalize(a int) {
+	stack := []int{a}
+	for len(stack) > 0 {
+		a := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		b := s.halfedges[a]
+		if b < 0 {
+			continue
+		}
+		a0, b0 := a-a%3, b-b%3
+		al, ar := a0+(a+1)%3, a0+(a+2)%3
+		br, bl := b0+(b+1)%3, b0+(b+2)%3
+		// a runs from pa to pb, with pc opposite it and pd opposite b
+		pa, pb, pc, pd := s.triangles[a], s.triangles[al], s.triangles[ar], s.triangles[bl]
+		if !delaunayInCircle(s.pts[pa], s.pts[pb], s.pts[pc], s.pts[pd]) {
+			continue
+		}
+		// the triangles become (pd, pb, pc) and (pc, pa, pd)
+		s.triangles[a], s.triangles[b] = pd, pc
+		hbl, har := s.halfedges[bl], s.halfedges[ar]
+		s.link(a, hbl)
+		s.link(b, har)
+		s.link(ar, bl)
+		// hull edges that moved to another half-edge
+		if hbl < 0 {
+			s.hullTri[pd] = a
+		}
+		if har < 0 {
+			s.hullTri[pc] = b
+		}
+		stack = append(stack, a, br)
+	}
+}
@@ -0,0 +1,178 @@
+package gaul
+
+import "math"
+
+// ScalarField maps a point to a tone, from 0 (white, no lines) to 1 (black). Values
+// outside that range are clamped.
+type ScalarField func(p Point) float64
+
+// NoiseField returns a field that follows rng.Noise2D, rescaled from [-1, 1] to [0, 1].
+// The scale, offsets and octaves of the noise are taken from rng when it's sampled.
+func NoiseField(rng *Rng) ScalarField {
+	return func(p Point) float64 {
+		return (rng.Noise2D(p.X, p.Y) + 1) / 2
+	}
+}
+
+// TonalHatchOptions configures [TonalHatch]
+type TonalHatchOptions struct {
+	// Angle is the direction of the lines in the first layer, in radians
+	Angle float64
+	// Layers is the number of line layers; each covers an equal share of the tone
+	// range, so the darkest areas get every layer. Defaults to 1.
+	Layers int
+	// LayerAngle is the rotation from one layer to the next. Defaults to Pi/Layers.
+	LayerAngle float64
+	// Spacing is the distance between the lines of a layer where it is fully dark
+	Spacing float64
+	// StepLength is the distance between samples of the field along a line, which
+	// is the finest resolution at which strokes start and stop. Defaults to Spacing.
+	StepLength float64
+	// MinStroke drops strokes shorter than this
+	MinStroke float64
+	// AngleShift turns each stroke about its middle by AngleShift times the tone
+	// there, so that the direction of the lines follows the field too. Turned
+	// strokes are clipped to the region again.
+	AngleShift float64
+	// Jitter, between 0 and 1, mixes random values into the tone at which each line
+	// switches on, which breaks up regular patterns in flat areas
+	Jitter float64
+	// Rule picks the parts of the region that strokes are drawn in
+	Rule FillRule
+}
+
+func (o TonalHatchOptions) layers() int {
+	if o.Layers < 1 {
+		return 1
+	}
+	return o.Layers
+}
+
+func (o TonalHatchOptions) layerAngle() float64 {
+	if o.LayerAngle == 0 {
+		return Pi / float64(o.layers())
+	}
+	return o.LayerAngle
+}
+
+func (o TonalHatchOptions) stepLength() float64 {
+	if o.StepLength <= 0 {
+		return o.Spacing
+	}
+	return o.StepLength
+}
+
+// TonalHatch shades a region described by closed curves with straight strokes whose
+// density follows the tone of a field, for reproducing images and noise on a pen
+// plotter. If field is nil the region is shaded with [NoiseField] of rng, and if rng
+// is nil a generator seeded with 0 is used.
+//
+// Each layer is a parallel hatch at the given spacing in which every line has a
+// threshold tone, spread evenly over the lines so that a fraction t of them is drawn
+// where the tone is t. Lines are sampled along their length and drawn only where the
+// tone is above their threshold, so both the number of lines and the length of the
+// strokes vary with the field. With several layers the first one covers the lightest
+// part of the tone range and the others, at different angles, cross-hatch the darker
+// parts. With AngleShift the strokes also turn with the tone.
+//
+// The strokes are returned layer by layer, and in each layer row by row along the
+// lines. The jitter is drawn from rng, which is advanced, so the output depends on
+// the state of rng and not just its seed: generators freshly made with the same seed
+// and noise settings give the same strokes, but a second call with the same rng
+// jitters differently.
+func TonalHatch(region []Curve, field ScalarField, rng *Rng, opts TonalHatchOptions) []Curve {
+	if opts.Spacing <= 0 {
+		return nil
+	}
+	if rng == nil {
+		r := NewRng(0)
+		rng = &r
+	}
+	if field == nil {
+		field = NoiseField(rng)
+	}
+	cl := NewClipper(region, opts.Rule)
+	layers := opts.layers()
+	step := opts.stepLength()
+	jitter := Clamp(0, 1, opts.Jitter)
+	var out []Curve
+	for k := 0; k < layers; k++ {
+		angle := opts.Angle + float64(k)*opts.layerAngle()
+		// tone of this layer's share of the range, from 0 to 1
+		tone := func(p Point) float64 {
+			return Clamp(0, 1, Clamp(0, 1, field(p))*float64(layers)-float64(k))
+		}
+		for r, row := range hatchRows(cl, region, angle, opts.Spacing) {
+			threshold := vanDerCorput(r + 1)
+			if jitter > 0 {
+				threshold = (1-jitter)*threshold + jitter*rng.Prng.Float64()
+			}
+			for _, l := range row {
+				out = appendToneStrokes(out, l, step, opts.MinStroke, func(p Point) bool {
+					return tone(p) > threshold
+				})
+			}
+		}
+	}
+	if opts.AngleShift == 0 {
+		return out
+	}
+	turned := make([]Curve, 0, len(out))
+	for _, c := range out {
+		l := Line{P: c.Points[0], Q: c.Points[1]}
+		m := l.Midpoint()
+		a := math.Atan2(l.Q.Y-l.P.Y, l.Q.X-l.P.X) + opts.AngleShift*Clamp(0, 1, field(m))
+		h := Vec2{X: math.Cos(a), Y: math.Sin(a)}.Scale(l.Length() / 2)
+		l = Line{P: Point{X: m.X - h.X, Y: m.Y - h.Y}, Q: Point{X: m.X + h.X, Y: m.Y + h.Y}}
+		for _, k := range cl.ClipLine(l, true) {
+			if k.Length() >= opts.MinStroke {
+				turned = append(turned, Curve{Points: []Point{k.P, k.Q}})
+			}
+		}
+	}
+	return turned
+}
+
+// appendToneStrokes appends the parts of l where dark holds, sampled at the middle of
+// steps of about the given length
+func appendToneStrokes(out []Curve, l Line, step, minStroke float64, dark func(Point) bool) []Curve {
+	n := int(math.Ceil(l.Length() / step))
+	if n < 1 {
+		n = 1
+	}
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		p := l.P.Lerp(l.Q, float64(start)/float64(n))
+		q := l.P.Lerp(l.Q, float64(end)/float64(n))
+		if Distance(p, q) >= minStroke {
+			out = append(out, Curve{Points: []Point{p, q}})
+		}
+		start = -1
+	}
+	for i := 0; i < n; i++ {
+		m := l.P.Lerp(l.Q, (float64(i)+0.5)/float64(n))
+		if dark(m) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(n)
+	return out
+}
+
+// vanDerCorput returns the i-th element of the base 2 van der Corput sequence, which
+// fills [0, 1) evenly at every length
+func vanDerCorput(i int) float64 {
+	v, f := 0.0, 0.5
+	for ; i > 0; i /= 2 {
+		v += f * float64(i%2)
+		f /= 2
+	}
+	return v
+}
@@ -0,0 +1,101 @@
+package gaul
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTonalHatch_flatTones(t *testing.T) {
+	region := []Curve{square(0, 0, 10)}
+	rng := NewRng(1)
+	opts := TonalHatchOptions{Spacing: 0.5}
+	full := HatchLines(region, EvenOdd, 0, 0.5)
+
+	black := TonalHatch(region, func(Point) float64 { return 1 }, &rng, opts)
+	require.Len(t, black, len(full))
+	assert.InDelta(t, linesLength(full), curvesLength(black), 1e-6)
+
+	assert.Empty(t, TonalHatch(region, func(Point) float64 { return 0 }, &rng, opts))
+
+	gray := TonalHatch(region, func(Point) float64 { return 0.5 }, &rng, opts)
+	assert.InEpsilon(t, linesLength(full)/2, curvesLength(gray), 0.1)
+
+	// the second layer only appears in the darker half of the range
+	opts.Layers = 2
+	light := TonalHatch(region, func(Point) float64 { return 0.45 }, &rng, opts)
+	for _, c := range light {
+		assert.InDelta(t, c.Points[0].Y, c.Points[1].Y, Smol)
+	}
+	dark := TonalHatch(region, func(Point) float64 { return 1 }, &rng, opts)
+	assert.InDelta(t, 2*linesLength(full), curvesLength(dark), 1e-6)
+}
+
+func TestTonalHatch_gradient(t *testing.T) {
+	region := []Curve{square(0, 0, 10)}
+	rng := NewRng(2)
+	strokes := TonalHatch(region, func(p Point) float64 { return p.X / 10 }, &rng,
+		TonalHatchOptions{Spacing: 0.2, StepLength: 0.1, Angle: Pi / 2})
+	var left, right float64
+	for _, c := range strokes {
+		if c.Points[0].X < 5 {
+			left += c.Length()
+		} else {
+			right += c.Length()
+		}
+	}
+	// ink grows with the tone: about a quarter of it on the left
+	assert.InEpsilon(t, 3*left, right, 0.15)
+	assertInsideRegion(t, strokes, region, EvenOdd)
+}
+
+func TestTonalHatch_deterministic(t *testing.T) {
+	region := []Curve{Circle{Center: Point{X: 50, Y: 50}, Radius: 40}.ToCurve(64)}
+	opts := TonalHatchOptions{Spacing: 2, Layers: 3, Jitter: 0.5, MinStroke: 1}
+	run := func(seed int64) []Curve {
+		rng := NewRng(seed)
+		rng.SetNoiseScaleX(0.03)
+		rng.SetNoiseScaleY(0.03)
+		return TonalHatch(region, nil, &rng, opts)
+	}
+	a, b := run(7), run(7)
+	require.NotEmpty(t, a)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, run(8))
+	for _, c := range a {
+		assert.GreaterOrEqual(t, c.Length(), 1.0)
+	}
+}
+
+func TestVanDerCorput(t *testing.T) {
+	want := []float64{0, 0.5, 0.25, 0.75, 0.125, 0.625}
+	for i, w := range want {
+		assert.Equal(t, w, vanDerCorput(i))
+	}
+}
+
+func TestTonalHatch_angleShift(t *testing.T) {
+	region := []Curve{square(0, 0, 10)}
+	// dark on the right, where the strokes turn a quarter turn from horizontal
+	field := func(p Point) float64 {
+		if p.X < 5 {
+			return 0.5
+		}
+		return 1
+	}
+	strokes := TonalHatch(region, field, nil, TonalHatchOptions{Spacing: 0.5, StepLength: 0.1, AngleShift: Pi / 2})
+	require.NotEmpty(t, strokes)
+	for _, c := range strokes {
+		l := Line{P: c.Points[0], Q: c.Points[1]}
+		d := Vec2FromLine(l).Normalize()
+		if l.Midpoint().X < 4.5 {
+			assert.InDelta(t, math.Cos(Pi/4), math.Abs(d.X), 1e-9)
+		} else if l.Midpoint().X > 5.5 {
+			assert.InDelta(t, 0, d.X, 1e-9)
+		}
+	}
+	assertInsideRegion(t, strokes, region, EvenOdd)
+	assert.Equal(t, strokes, TonalHatch(region, field, nil, TonalHatchOptions{Spacing: 0.5, StepLength: 0.1, AngleShift: Pi / 2}))
+}
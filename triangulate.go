@@ -0,0 +1,264 @@
+package gaul
+
+import (
+	"math"
+	"sort"
+)
+
+// TriangulatePolygon splits a region described by closed curves (outer boundaries and
+// holes, interpreted with the given fill rule) into triangles that cover exactly the
+// region. The region may be concave, have holes and consist of several pieces.
+//
+// The region is first normalized with [BooleanCurves]; every hole is then joined to
+// its enclosing boundary by a bridge to a visible vertex, and the resulting polygons
+// are cut into counterclockwise triangles by ear clipping.
+func TriangulatePolygon(region []Curve, rule FillRule) []Triangle {
+	loops := BooleanCurves(region, nil, BoolUnion, rule)
+	var outers, holes [][]Point
+	for _, c := range loops {
+		if polygonSignedArea(c.Points) > 0 {
+			outers = append(outers, c.Points)
+		} else {
+			holes = append(holes, c.Points)
+		}
+	}
+	// every hole belongs to the smallest boundary around it
+	owned := make([][][]Point, len(outers))
+	for _, h := range holes {
+		p := pointLeftOfEdge(h[0], h[1])
+		best, bestArea := -1, math.Inf(1)
+		for i, o := range outers {
+			if a := polygonSignedArea(o); a < bestArea && polygonWinding(o, p) != 0 {
+				best, bestArea = i, a
+			}
+		}
+		if best >= 0 {
+			owned[best] = append(owned[best], h)
+		}
+	}
+	var tris []Triangle
+	for i, o := range outers {
+		tris = earClip(bridgeHoles(o, owned[i]), tris)
+	}
+	return tris
+}
+
+// Triangulate splits the area enclosed by the curve into triangles, see
+// [TriangulatePolygon]. The curve is treated as closed.
+func (c *Curve) Triangulate() []Triangle {
+	return TriangulatePolygon([]Curve{*c}, NonZero)
+}
+
+// pointLeftOfEdge returns a point just to the left of the middle of segment a-b
+func pointLeftOfEdge(a, b Point) Point {
+	m := Midpoint(a, b)
+	d := Vec2FromPoints(a, b).Scale(1e-6)
+	return Point{X: m.X - d.Y, Y: m.Y + d.X}
+}
+
+// bridgeHoles merges clockwise holes into a counterclockwise outer polygon, giving a
+// single weakly simple polygon. Holes are taken from right to left; each is joined by a
+// pair of coincident edges from its rightmost vertex to a vertex of the polygon that it
+// can see, found as in Eberly's "Triangulation by Ear Clipping".
+func bridgeHoles(outer []Point, holes [][]Point) []Point {
+	poly := append([]Point(nil), outer...)
+	type hole struct {
+		pts   []Point
+		right int
+	}
+	hs := make([]hole, len(holes))
+	for i, h := range holes {
+		right := 0
+		for k, p := range h {
+			if p.X > h[right].X || (p.X == h[right].X && p.Y < h[right].Y) {
+				right = k
+			}
+		}
+		hs[i] = hole{pts: h, right: right}
+	}
+	sort.SliceStable(hs, func(i, j int) bool {
+		return hs[i].pts[hs[i].right].X > hs[j].pts[hs[j].right].X
+	})
+	for _, h := range hs {
+		m := h.pts[h.right]
+		p := bridgeVertex(poly, m)
+		if p < 0 {
+			continue
+		}
+		// a vertex visited twice (the end of an earlier bridge) must be joined at the
+		// visit whose corner faces the hole
+		for k, q := range poly {
+			if q.IsEqual(poly[p]) && inCorner(poly[(k+len(poly)-1)%len(poly)], q, poly[(k+1)%len(poly)], m) {
+				p = k
+				break
+			}
+		}
+		merged := make([]Point, 0, len(poly)+len(h.pts)+2)
+		merged = append(merged, poly[:p+1]...)
+		for k := 0; k <= len(h.pts); k++ {
+			merged = append(merged, h.pts[(h.right+k)%len(h.pts)])
+		}
+		merged = append(merged, poly[p:]...)
+		poly = merged
+	}
+	return poly
+}
+
+// bridgeVertex returns the index of a vertex of poly visible from m, a point inside it
+func bridgeVertex(poly []Point, m Point) int {
+	n := len(poly)
+	// the closest edge hit by a ray from m towards +x
+	hit, hitX := -1, math.Inf(1)
+	for i := 0; i < n; i++ {
+		a, b := poly[i], poly[(i+1)%n]
+		if (a.Y > m.Y) == (b.Y > m.Y) && a.Y != m.Y && b.Y != m.Y {
+			continue
+		}
+		if a.Y == b.Y {
+			// a horizontal edge on the ray is hit at its nearer end
+			if a.Y == m.Y {
+				for _, k := range []int{i, (i + 1) % n} {
+					if poly[k].X >= m.X && poly[k].X < hitX {
+						hit, hitX = k, poly[k].X
+					}
+				}
+			}
+			continue
+		}
+		x := a.X + (m.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if x < m.X || x >= hitX {
+			continue
+		}
+		switch {
+		case x == a.X && m.Y == a.Y:
+			hit, hitX = i, x
+		case x == b.X && m.Y == b.Y:
+			hit, hitX = (i+1)%n, x
+		default:
+			// an edge crossed in its interior: its endpoint furthest right is a
+			// candidate
+			k := i
+			if b.X > a.X {
+				k = (i + 1) % n
+			}
+			hit, hitX = -2-k, x
+		}
+	}
+	if hit == -1 {
+		return -1
+	}
+	if hit >= 0 {
+		return hit
+	}
+	p := -2 - hit
+	hitPoint := Point{X: hitX, Y: m.Y}
+	// reflex vertices inside the triangle m, hit point, candidate may block the
+	// view; the one making the smallest angle with the ray is visible
+	tri := Triangle{A: m, B: hitPoint, C: poly[p]}
+	best, bestAngle, bestDist := p, math.Inf(1), math.Inf(1)
+	for k := 0; k < n; k++ {
+		q := poly[k]
+		if k == p || q.X < m.X {
+			continue
+		}
+		if orient2(poly[(k+n-1)%n], q, poly[(k+1)%n]) > 0 || !triangleContainsOrTouches(tri, q) {
+			continue
+		}
+		angle := math.Abs(math.Atan2(q.Y-m.Y, q.X-m.X))
+		dist := SquaredDistance(m, q)
+		if angle < bestAngle || (angle == bestAngle && dist < bestDist) {
+			best, bestAngle, bestDist = k, angle, dist
+		}
+	}
+	return best
+}
+
+// inCorner reports whether q lies within the interior angle at vertex v of a
+// counterclockwise polygon with neighbours u and w
+func inCorner(u, v, w, q Point) bool {
+	if orient2(u, v, w) >= 0 {
+		return orient2(v, w, q) >= 0 && orient2(u, v, q) >= 0
+	}
+	return orient2(v, w, q) >= 0 || orient2(u, v, q) >= 0
+}
+
+// earClip appends the triangles of a counterclockwise, weakly simple polygon to tris
+func earClip(poly []Point, tris []Triangle) []Triangle {
+	n := len(poly)
+	if n < 3 {
+		return tris
+	}
+	prev := make([]int, n)
+	next := make([]int, n)
+	for i := range poly {
+		prev[i], next[i] = (i+n-1)%n, (i+1)%n
+	}
+	grid := newPointGrid(poly)
+	remove := func(i int) {
+		grid.remove(i, poly[i])
+		next[prev[i]], prev[next[i]] = next[i], prev[i]
+	}
+	isEar := func(i int) bool {
+		a, b, c := prev[i], i, next[i]
+		tri := Triangle{A: poly[a], B: poly[b], C: poly[c]}
+		blocked := false
+		grid.query(tri.Boundary(), func(k int) bool {
+			q := poly[k]
+			if k == a || k == b || k == c || q.IsEqual(tri.A) || q.IsEqual(tri.B) || q.IsEqual(tri.C) {
+				return true
+			}
+			// only vertices where the polygon turns right can reach into an ear
+			if orient2(poly[prev[k]], q, poly[next[k]]) > 0 {
+				return true
+			}
+			if triangleContainsOrTouches(tri, q) {
+				blocked = true
+				return false
+			}
+			return true
+		})
+		return !blocked
+	}
+
+	remaining := n
+	i := 0
+	for remaining > 3 {
+		clipped := false
+		for tries := 0; tries < remaining; tries, i = tries+1, next[i] {
+			turn := orient2(poly[prev[i]], poly[i], poly[next[i]])
+			if turn == 0 {
+				// a flat vertex or a zero-width spike encloses nothing
+				clipped = true
+			} else if turn > 0 && isEar(i) {
+				tris = append(tris, Triangle{A: poly[prev[i]], B: poly[i], C: poly[next[i]]})
+				clipped = true
+			}
+			if clipped {
+				j := next[i]
+				remove(i)
+				remaining--
+				i = j
+				break
+			}
+		}
+		if !clipped {
+			// rounding left no valid ear: cut the most convex vertex to make progress
+			best, bestTurn := -1, math.Inf(-1)
+			for k, tries := i, 0; tries < remaining; k, tries = next[k], tries+1 {
+				if turn := orient2(poly[prev[k]], poly[k], poly[next[k]]); turn > bestTurn {
+					best, bestTurn = k, turn
+				}
+			}
+			if bestTurn > 0 {
+				tris = append(tris, Triangle{A: poly[prev[best]], B: poly[best], C: poly[next[best]]})
+			}
+			i = next[best]
+			remove(best)
+			remaining--
+		}
+	}
+	if orient2(poly[prev[i]], poly[i], poly[next[i]]) > 0 {
+		tris = append(tris, Triangle{A: poly[prev[i]], B: poly[i], C: poly[next[i]]})
+	}
+	return tris
+}
@@ -0,0 +1,147 @@
+package gaul
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkTriangulation verifies that triangles are counterclockwise, lie in the region
+// and add up to its area
+func checkTriangulation(t *testing.T, tris []Triangle, region []Curve, rule FillRule) {
+	t.Helper()
+	cl := NewClipper(region, rule)
+	var sum float64
+	for _, tri := range tris {
+		a := orient2(tri.A, tri.B, tri.C) / 2
+		assert.Greater(t, a, 0.0)
+		sum += a
+		assert.True(t, cl.contains(tri.Centroid()), "triangle %v is outside", tri)
+	}
+	want := signedAreaSum(BooleanCurves(region, nil, BoolUnion, rule))
+	assert.InDelta(t, want, sum, 1e-6*math.Max(1, want))
+}
+
+func TestTriangulatePolygon_concave(t *testing.T) {
+	u := Curve{Closed: true, Points: []Point{
+		{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 3}, {X: 2, Y: 3},
+		{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 3}, {X: 0, Y: 3},
+	}}
+	tris := u.Triangulate()
+	assert.Len(t, tris, 6)
+	checkTriangulation(t, tris, []Curve{u}, NonZero)
+
+	// clockwise input is triangulated just the same
+	u.Reverse()
+	checkTriangulation(t, u.Triangulate(), []Curve{u}, NonZero)
+
+	star := Curve{Closed: true}
+	for i := 0; i < 40; i++ {
+		r := 5.0
+		if i%2 == 1 {
+			r = 1.5
+		}
+		a := float64(i) * Tau / 40
+		star.Points = append(star.Points, Point{X: r * math.Cos(a), Y: r * math.Sin(a)})
+	}
+	tris = star.Triangulate()
+	assert.Len(t, tris, 38)
+	checkTriangulation(t, tris, []Curve{star}, NonZero)
+}
+
+func TestTriangulatePolygon_holes(t *testing.T) {
+	hole := square(1, 1, 2)
+	hole.Reverse()
+	region := []Curve{square(0, 0, 4), hole}
+	tris := TriangulatePolygon(region, NonZero)
+	// n + 2h - 2 triangles for n vertices and h holes
+	assert.Len(t, tris, 8)
+	checkTriangulation(t, tris, region, NonZero)
+
+	// a row of holes lined up with each other, and holes sharing the ray to the right
+	region = []Curve{square(0, 0, 20)}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			region = append(region, square(1+4*float64(i), 2+6*float64(j), 2))
+		}
+	}
+	checkTriangulation(t, TriangulatePolygon(region, EvenOdd), region, EvenOdd)
+
+	// holes in an island in a hole
+	region = []Curve{
+		Circle{Center: Point{X: 0, Y: 0}, Radius: 10}.ToCurve(48),
+		Circle{Center: Point{X: 0, Y: 0}, Radius: 7}.ToCurve(40),
+		Circle{Center: Point{X: 0, Y: 0}, Radius: 5}.ToCurve(32),
+		Circle{Center: Point{X: 2, Y: 0}, Radius: 1}.ToCurve(12),
+		Circle{Center: Point{X: -2, Y: 1}, Radius: 1}.ToCurve(12),
+	}
+	checkTriangulation(t, TriangulatePolygon(region, EvenOdd), region, EvenOdd)
+}
+
+func TestTriangulatePolygon_random(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for trial := 0; trial < 20; trial++ {
+		// a random radial polygon with random circular holes that stay inside it
+		outer := Curve{Closed: true}
+		n := 10 + rng.Intn(40)
+		for i := 0; i < n; i++ {
+			a := float64(i) * Tau / float64(n)
+			r := 20 + 10*rng.Float64()
+			outer.Points = append(outer.Points, Point{X: r * math.Cos(a), Y: r * math.Sin(a)})
+		}
+		region := []Curve{outer}
+		for h := 0; h < rng.Intn(6); h++ {
+			c := Circle{
+				Center: Point{X: rng.Float64()*24 - 12, Y: rng.Float64()*24 - 12},
+				Radius: 1 + 3*rng.Float64(),
+			}.ToCurve(5 + rng.Intn(10))
+			c.Reverse()
+			region = append(region, c)
+		}
+		checkTriangulation(t, TriangulatePolygon(region, NonZero), region, NonZero)
+	}
+}
+
+func TestRng_UniformRandomPointsInTriangles(t *testing.T) {
+	hole := square(1, 1, 2)
+	hole.Reverse()
+	region := []Curve{square(0, 0, 4), hole}
+	tris := TriangulatePolygon(region, NonZero)
+	rng := NewRng(5)
+	pts := rng.UniformRandomPointsInTriangles(4000, tris)
+	require.Len(t, pts, 4000)
+	cl := NewClipper(region, NonZero)
+	left := 0
+	for _, p := range pts {
+		assert.True(t, cl.contains(p))
+		if p.X < 1 {
+			left++
+		}
+	}
+	// the strip left of the hole has a third of the area
+	assert.InDelta(t, 4000.0/3, float64(left), 100)
+
+	again := NewRng(5)
+	assert.Equal(t, pts, again.UniformRandomPointsInTriangles(4000, tris))
+	assert.Empty(t, rng.UniformRandomPointsInTriangles(10, nil))
+}
+
+func TestTriangulatePolygon_thin(t *testing.T) {
+	// a sliver whose bounding box has almost no area used to give a grid of tiny
+	// cells for the ears to walk
+	c := Curve{Closed: true}
+	for i := 0; i <= 2000; i++ {
+		c.Points = append(c.Points, Point{X: float64(i), Y: 0})
+	}
+	c.Points = append(c.Points, Point{X: 1000, Y: 1e-9})
+	tris := earClip(c.Points, nil)
+	assert.Len(t, tris, len(c.Points)-2)
+	var sum float64
+	for _, tri := range tris {
+		sum += orient2(tri.A, tri.B, tri.C) / 2
+	}
+	assert.InDelta(t, 1e-6, sum, 1e-12)
+}
@@ -620,7 +620,6 @@ func (s *Voronoi) removeBeachsection(beachsection *Beachsection) {
 	previous := beachsection.node.previous
 	next := beachsection.node.next
 	disappearingTransitions := BeachsectionPtrs{beachsection}
-	abs_fn := math.Abs
 
 	// remove collapsed beachsection from beachline
 	s.detachBeachsection(beachsection)
@@ -634,11 +633,19 @@ func (s *Voronoi) removeBeachsection(beachsection *Beachsection) {
 	// beach sections on the beachline, since they obviously are unconstrained
 	// on their left/right side.
 
-	// look left
+	// look left: a neighbour collapses at the same vertex when the site beyond it
+	// lies on the same circle as the three sites of this event, which the exact
+	// in-circle test decides without depending on how the centers round
+	circleSites := [3]Point{
+		previous.value.(*Beachsection).site,
+		beachsection.site,
+		next.value.(*Beachsection).site,
+	}
+	sameCircle := func(p Point) bool {
+		return incircle(circleSites[0], circleSites[1], circleSites[2], p) == 0
+	}
 	lArc := previous.value.(*Beachsection)
-	for lArc.circleEvent != nil &&
-		abs_fn(x-lArc.circleEvent.x) < 1e-9 &&
-		abs_fn(y-lArc.circleEvent.ycenter) < 1e-9 {
+	for lArc.circleEvent != nil && sameCircle(lArc.node.previous.value.(*Beachsection).site) {
 
 		previous = lArc.node.previous
 		disappearingTransitions.appendLeft(lArc)
@@ -654,9 +661,7 @@ func (s *Voronoi) removeBeachsection(beachsection *Beachsection) {
 
 	// look right
 	var rArc = next.value.(*Beachsection)
-	for rArc.circleEvent != nil &&
-		abs_fn(x-rArc.circleEvent.x) < 1e-9 &&
-		abs_fn(y-rArc.circleEvent.ycenter) < 1e-9 {
+	for rArc.circleEvent != nil && sameCircle(rArc.node.next.value.(*Beachsection).site) {
 		next = rArc.node.next
 		disappearingTransitions.appendRight(rArc)
 		s.detachBeachsection(rArc) // mark for reuse
@@ -948,10 +953,11 @@ func (s *Voronoi) attachCircleEvent(arc *Beachsection) {
 	// collapse, hence it can't end up as a vertex (we reuse 'd' here, which
 	// sign is reverse of the orientation, hence we reverse the test.
 	// http://en.wikipedia.org/wiki/Curve_orientation#Orientation_of_a_simple_polygon
-	// rhill 2011-05-21: Nasty finite precision error which caused circumcircle() to
-	// return infinites: 1e-12 seems to fix the problem.
-	d := 2 * (ax*cy - ay*cx)
-	if d >= -2e-12 {
+	// The orientation is decided by the exact predicate, so nearly collinear
+	// sites are neither dropped nor given a circle on the wrong side; a
+	// determinant too small to divide by would put the circle at infinity.
+	d := 2 * orient2(cSite, LeftSite, RightSite)
+	if d >= 0 || math.IsInf(1/d, 0) {
 		return
 	}
 
@@ -1023,6 +1029,32 @@ type BBox struct {
 	Xl, Xr, Yt, Yb float64
 }
 
+// the sides of a BBox, as clipEdge cuts edges at them
+const (
+	clipNone = iota
+	clipLeft
+	clipRight
+	clipTop
+	clipBottom
+)
+
+// onSide puts p, which rounding may have moved a little off the given side, exactly
+// on it and within the box, so that the cells can be closed along the box by
+// comparing coordinates exactly
+func (b BBox) onSide(p Point, side int) Point {
+	switch side {
+	case clipLeft:
+		p.X = b.Xl
+	case clipRight:
+		p.X = b.Xr
+	case clipTop:
+		p.Y = b.Yt
+	case clipBottom:
+		p.Y = b.Yb
+	}
+	return Point{X: Clamp(b.Xl, b.Xr, p.X), Y: Clamp(b.Yt, b.Yb, p.Y)}
+}
+
 // Create new Bounding Box
 func NewBBox(xl, xr, yt, yb float64) BBox {
 	return BBox{xl, xr, yt, yb}
@@ -1059,7 +1091,7 @@ func connectEdge(edge *Edge, bbox BBox) bool {
 	var fm, fb float64
 
 	// get the line equation of the bisector if line is not vertical
-	if !equalWithEpsilon(ry, ly) {
+	if ry != ly {
 		fm = (lx - rx) / (ry - ly)
 		fb = fy - fm*fx
 	}
@@ -1077,7 +1109,7 @@ func connectEdge(edge *Edge, bbox BBox) bool {
 	// bounding box to use to determine a reasonable start point
 
 	// special case: vertical line
-	if equalWithEpsilon(ry, ly) {
+	if ry == ly {
 		// doesn't intersect with viewport
 		if fx < xl || fx >= xr {
 			return false
@@ -1159,6 +1191,8 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 	by := edge.Vb.Y
 	t0 := float64(0)
 	t1 := float64(1)
+	// the sides of bbox the edge is cut at, if any
+	side0, side1 := clipNone, clipNone
 	dx := bx - ax
 	dy := by - ay
 
@@ -1172,13 +1206,13 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 		if r < t0 {
 			return false
 		} else if r < t1 {
-			t1 = r
+			t1, side1 = r, clipLeft
 		}
 	} else if dx > 0 {
 		if r > t1 {
 			return false
 		} else if r > t0 {
-			t0 = r
+			t0, side0 = r, clipLeft
 		}
 	}
 	// right
@@ -1191,13 +1225,13 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 		if r > t1 {
 			return false
 		} else if r > t0 {
-			t0 = r
+			t0, side0 = r, clipRight
 		}
 	} else if dx > 0 {
 		if r < t0 {
 			return false
 		} else if r < t1 {
-			t1 = r
+			t1, side1 = r, clipRight
 		}
 	}
 
@@ -1211,13 +1245,13 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 		if r < t0 {
 			return false
 		} else if r < t1 {
-			t1 = r
+			t1, side1 = r, clipTop
 		}
 	} else if dy > 0 {
 		if r > t1 {
 			return false
 		} else if r > t0 {
-			t0 = r
+			t0, side0 = r, clipTop
 		}
 	}
 	// bottom
@@ -1230,13 +1264,13 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 		if r > t1 {
 			return false
 		} else if r > t0 {
-			t0 = r
+			t0, side0 = r, clipBottom
 		}
 	} else if dy > 0 {
 		if r < t0 {
 			return false
 		} else if r < t1 {
-			t1 = r
+			t1, side1 = r, clipBottom
 		}
 	}
 
@@ -1247,7 +1281,7 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 	// than modifying the existing one, since the existing
 	// one is likely shared with at least another edge
 	if t0 > 0 {
-		edge.Va.Point = Point{ax + t0*dx, ay + t0*dy}
+		edge.Va.Point = bbox.onSide(Point{ax + t0*dx, ay + t0*dy}, side0)
 	}
 
 	// if t1 < 1, vb needs to change
@@ -1255,24 +1289,12 @@ func clipEdge(edge *Edge, bbox BBox) bool {
 	// than modifying the existing one, since the existing
 	// one is likely shared with at least another edge
 	if t1 < 1 {
-		edge.Vb.Point = Point{ax + t1*dx, ay + t1*dy}
+		edge.Vb.Point = bbox.onSide(Point{ax + t1*dx, ay + t1*dy}, side1)
 	}
 
 	return true
 }
 
-func equalWithEpsilon(a, b float64) bool {
-	return math.Abs(a-b) < 1e-9
-}
-
-func lessThanWithEpsilon(a, b float64) bool {
-	return b-a > 1e-9
-}
-
-func greaterThanWithEpsilon(a, b float64) bool {
-	return a-b > 1e-9
-}
-
 // Connect/cut edges at bounding box
 func (s *Voronoi) clipEdges(bbox BBox) {
 	// connect all dangling edges to bounding box
@@ -1334,30 +1356,30 @@ func (s *Voronoi) closeCells(bbox BBox) {
 				va := endpoint
 				vb := endpoint
 				// walk downward along left side
-				if equalWithEpsilon(endpoint.X, xl) && lessThanWithEpsilon(endpoint.Y, yb) {
-					if equalWithEpsilon(startpoint.X, xl) {
+				if endpoint.X == xl && endpoint.Y < yb {
+					if startpoint.X == xl {
 						vb = Point{xl, startpoint.Y}
 					} else {
 						vb = Point{xl, yb}
 					}
 
 					// walk rightward along bottom side
-				} else if equalWithEpsilon(endpoint.Y, yb) && lessThanWithEpsilon(endpoint.X, xr) {
-					if equalWithEpsilon(startpoint.Y, yb) {
+				} else if endpoint.Y == yb && endpoint.X < xr {
+					if startpoint.Y == yb {
 						vb = Point{startpoint.X, yb}
 					} else {
 						vb = Point{xr, yb}
 					}
 					// walk upward along right side
-				} else if equalWithEpsilon(endpoint.X, xr) && greaterThanWithEpsilon(endpoint.Y, yt) {
-					if equalWithEpsilon(startpoint.X, xr) {
+				} else if endpoint.X == xr && endpoint.Y > yt {
+					if startpoint.X == xr {
 						vb = Point{xr, startpoint.Y}
 					} else {
 						vb = Point{xr, yt}
 					}
 					// walk leftward along top side
-				} else if equalWithEpsilon(endpoint.Y, yt) && greaterThanWithEpsilon(endpoint.X, xl) {
-					if equalWithEpsilon(startpoint.Y, yt) {
+				} else if endpoint.Y == yt && endpoint.X > xl {
+					if startpoint.Y == yt {
 						vb = Point{startpoint.X, yt}
 					} else {
 						vb = Point{xl, yt}
@@ -1547,34 +1569,7 @@ func voronoiCellPolygon(c *Cell) []Point {
 	if len(pts) >= 2 && voronoiPointEqual(pts[0], pts[len(pts)-1]) {
 		pts = pts[:len(pts)-1]
 	}
-	return voronoiEnsurePolygonCCW(pts)
-}
-
-// voronoiPolygonSignedArea2 is twice the signed area (shoelace sum) of a simple polygon.
-// Positive means counterclockwise when +Y is up (standard Cartesian).
-func voronoiPolygonSignedArea2(pts []Point) float64 {
-	var s float64
-	n := len(pts)
-	for i := 0; i < n; i++ {
-		j := (i + 1) % n
-		s += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
-	}
-	return s
-}
-
-// voronoiEnsurePolygonCCW reverses pts in place if the winding is clockwise, so that
-// the polygon is counterclockwise. Degenerate or near-zero-area polygons are unchanged.
-func voronoiEnsurePolygonCCW(pts []Point) []Point {
-	if len(pts) < 3 {
-		return pts
-	}
-	if voronoiPolygonSignedArea2(pts) >= -Smol {
-		return pts
-	}
-	for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
-		pts[i], pts[j] = pts[j], pts[i]
-	}
-	return pts
+	return ensurePolygonCCW(pts)
 }
 
 func voronoiPointEqual(a, b Point) bool {
@@ -1610,7 +1605,7 @@ func voronoiIsConvexCCW(poly []Point) bool {
 			return false
 		}
 	}
-	return voronoiPolygonSignedArea2(poly) > Smol
+	return polygonSignedArea(poly) > Smol/2
 }
 
 // voronoiIntersectSegmentLine returns the intersection of the closed segment s–e with
@@ -1736,7 +1731,7 @@ func VoronoiWithRect(bounds Rect, sites []Point) ([]Curve, error) {
 	// One site: the sweep leaves no finite bisectors; the clipped cell is the full bounds.
 	if len(unique) == 1 {
 		full := bounds.ToCurve()
-		voronoiEnsurePolygonCCW(full.Points)
+		full.EnsureCCW()
 		bySite[pointKey{unique[0].X, unique[0].Y}] = full
 	}
 
@@ -1756,24 +1751,14 @@ func VoronoiWithRect(bounds Rect, sites []Point) ([]Curve, error) {
 // boundary must be [Curve.Closed] with at least three vertices, define a strictly
 // positive area, and form a simple convex polygon (for example a [Rect] from
 // [Rect.ToCurve], a triangle, or another Voronoi cell). Non-convex boundaries are not
-// supported because clipping is O(segments) per cell via convex half-plane cuts.
+// supported because clipping is O(segments) per cell via convex half-plane cuts; see
+// [VoronoiWithRegion] for those and for boundaries with holes.
 // Duplicate site coordinates yield identical curves. Sites must lie inside or on the
 // boundary polygon.
 func VoronoiWithCurve(boundary Curve, sites []Point) ([]Curve, error) {
-	if !boundary.Closed {
-		return nil, errors.New("gaul VoronoiWithCurve: boundary curve must be closed")
-	}
-	if len(boundary.Points) < 3 {
-		return nil, errors.New("gaul VoronoiWithCurve: boundary must have at least three points")
-	}
-	br := boundary.Boundary()
-	if br.W <= 0 || br.H <= 0 {
-		return nil, errors.New("gaul VoronoiWithCurve: boundary has empty axis-aligned extent")
-	}
-	clipPoly := append([]Point(nil), boundary.Points...)
-	voronoiEnsurePolygonCCW(clipPoly)
-	if !voronoiIsConvexCCW(clipPoly) {
-		return nil, errors.New("gaul VoronoiWithCurve: boundary must be a simple convex polygon")
+	clipPoly, br, err := voronoiConvexBoundary("VoronoiWithCurve", boundary)
+	if err != nil {
+		return nil, err
 	}
 	if len(sites) == 0 {
 		return nil, nil
@@ -1797,7 +1782,7 @@ func VoronoiWithCurve(boundary Curve, sites []Point) ([]Curve, error) {
 			curve.Points = nil
 		} else {
 			curve.Points = append(curve.Points, clipped...)
-			voronoiEnsurePolygonCCW(curve.Points)
+			curve.EnsureCCW()
 		}
 		k := pointKey{c.Site.X, c.Site.Y}
 		bySite[k] = curve
@@ -1807,7 +1792,7 @@ func VoronoiWithCurve(boundary Curve, sites []Point) ([]Curve, error) {
 			Closed: true,
 			Points: append([]Point(nil), clipPoly...),
 		}
-		voronoiEnsurePolygonCCW(full.Points)
+		full.EnsureCCW()
 		bySite[pointKey{unique[0].X, unique[0].Y}] = full
 	}
 
@@ -1817,3 +1802,133 @@ func VoronoiWithCurve(boundary Curve, sites []Point) ([]Curve, error) {
 	}
 	return out, nil
 }
+
+// voronoiConvexBoundary checks that boundary is a closed convex polygon with some
+// area, returning its points counterclockwise and its bounding box
+func voronoiConvexBoundary(name string, boundary Curve) ([]Point, Rect, error) {
+	if !boundary.Closed {
+		return nil, Rect{}, fmt.Errorf("gaul %s: boundary curve must be closed", name)
+	}
+	if len(boundary.Points) < 3 {
+		return nil, Rect{}, fmt.Errorf("gaul %s: boundary must have at least three points", name)
+	}
+	br := boundary.Boundary()
+	if br.W <= 0 || br.H <= 0 {
+		return nil, Rect{}, fmt.Errorf("gaul %s: boundary has empty axis-aligned extent", name)
+	}
+	poly := append([]Point(nil), boundary.Points...)
+	ensurePolygonCCW(poly)
+	if !voronoiIsConvexCCW(poly) {
+		return nil, Rect{}, fmt.Errorf("gaul %s: boundary must be a simple convex polygon", name)
+	}
+	return poly, br, nil
+}
+
+// VoronoiRegionOptions configures [VoronoiWithRegion]
+type VoronoiRegionOptions struct {
+	// Rule picks the parts of the region that the cells are clipped to, and the
+	// sites that count as inside it
+	Rule FillRule
+	// SkipOutside leaves sites outside the region out of the diagram, with no cell,
+	// instead of returning an error
+	SkipOutside bool
+}
+
+// VoronoiWithRegion computes the Euclidean Voronoi diagram for sites clipped to a
+// region described by closed curves: outer boundaries, which may be non-convex, and
+// holes, interpreted with opts.Rule. It runs Fortune's algorithm on the bounding box
+// of the region and intersects each cell with the region using [BooleanCurves].
+//
+// The result holds the clipped cell of sites[i] at index i as a list of closed curves
+// with the conventions of BooleanCurves: outer boundaries counterclockwise and holes
+// clockwise. A cell may fall apart into several pieces where the region is concave,
+// and contain holes of the region. Sites must lie inside the region or on its
+// boundary; with opts.SkipOutside the others get no cell and don't take part in the
+// diagram, otherwise they are an error. Duplicate site coordinates yield identical
+// cells.
+func VoronoiWithRegion(region []Curve, sites []Point, opts VoronoiRegionOptions) ([][]Curve, error) {
+	var all []Point
+	for _, c := range region {
+		all = append(all, c.Points...)
+	}
+	if len(all) < 3 {
+		return nil, errors.New("gaul VoronoiWithRegion: region must have at least three points")
+	}
+	br := (&Curve{Points: all}).Boundary()
+	if br.W <= 0 || br.H <= 0 {
+		return nil, errors.New("gaul VoronoiWithRegion: region has empty axis-aligned extent")
+	}
+	cl := NewClipper(region, opts.Rule)
+	if cl.g == nil {
+		return nil, errors.New("gaul VoronoiWithRegion: region has no edges")
+	}
+	if len(sites) == 0 {
+		return nil, nil
+	}
+	var kept []Point
+	for i, p := range sites {
+		if cl.contains(p) || cl.onBoundary(p) {
+			kept = append(kept, p)
+		} else if !opts.SkipOutside {
+			return nil, fmt.Errorf("gaul VoronoiWithRegion: site %d is not inside region", i)
+		}
+	}
+	out := make([][]Curve, len(sites))
+	if len(kept) == 0 {
+		return out, nil
+	}
+	unique := uniqueSitesFortune(kept)
+	bySite := make(map[pointKey][]Curve, len(unique))
+	if len(unique) == 1 {
+		// one site: the sweep leaves no finite bisectors and the cell is everything
+		bySite[pointKey{unique[0].X, unique[0].Y}] = voronoiClipCell(cl, region, opts.Rule, br.ToCurve().Points)
+	} else {
+		d := computeFortuneDiagram(unique, rectToBBox(br), true)
+		for _, c := range d.Cells {
+			if pts := voronoiCellPolygon(c); len(pts) >= 3 {
+				bySite[pointKey{c.Site.X, c.Site.Y}] = voronoiClipCell(cl, region, opts.Rule, pts)
+			}
+		}
+	}
+	for i, p := range sites {
+		if cell, ok := bySite[pointKey{p.X, p.Y}]; ok {
+			out[i] = cell
+		}
+	}
+	return out, nil
+}
+
+// voronoiClipCell intersects a convex cell with the region. Cells that no boundary
+// edge comes near are kept or dropped whole.
+func voronoiClipCell(cl *Clipper, region []Curve, rule FillRule, cell []Point) []Curve {
+	c := Curve{Points: append([]Point(nil), cell...), Closed: true}
+	c.EnsureCCW()
+	box := c.Boundary()
+	crossed := false
+	for _, e := range cl.candidateEdges(Point{X: box.X, Y: box.Y}, Point{X: box.X + box.W, Y: box.Y + box.H}) {
+		edge := cl.g.edges[e]
+		a, b := cl.g.pts[edge.a], cl.g.pts[edge.b]
+		if math.Max(a.X, b.X) >= box.X && math.Min(a.X, b.X) <= box.X+box.W &&
+			math.Max(a.Y, b.Y) >= box.Y && math.Min(a.Y, b.Y) <= box.Y+box.H {
+			crossed = true
+			break
+		}
+	}
+	if !crossed {
+		if cl.contains(pointInConvexPolygon(c.Points)) {
+			return []Curve{c}
+		}
+		return nil
+	}
+	return BooleanCurves([]Curve{c}, region, BoolIntersection, rule)
+}
+
+// pointInConvexPolygon returns the average of the vertices of a convex polygon
+func pointInConvexPolygon(pts []Point) Point {
+	var sx, sy float64
+	for _, p := range pts {
+		sx += p.X
+		sy += p.Y
+	}
+	return Point{X: sx / float64(len(pts)), Y: sy / float64(len(pts))}
+}
@@ -69,7 +69,7 @@ func TestVoronoiWithRect_cellPolygonsAreCCW(t *testing.T) {
 			if len(c.Points) < 3 {
 				continue
 			}
-			assert.GreaterOrEqual(t, voronoiPolygonSignedArea2(c.Points), -Smol,
+			assert.GreaterOrEqual(t, c.SignedArea(), -Smol/2,
 				"cell polygon should be counterclockwise (non-negative signed shoelace sum)")
 		}
 	}
@@ -214,6 +214,112 @@ func TestVoronoiWithCurve_nestedInParentCell(t *testing.T) {
 	assert.InDelta(t, boundary.Area(), sum, 1e-3)
 }
 
+func cellsArea(cells []Curve) float64 {
+	var sum float64
+	for _, c := range cells {
+		sum += c.SignedArea()
+	}
+	return sum
+}
+
+func TestVoronoiWithRegion_concave(t *testing.T) {
+	// a U: the square [0, 3]² without the notch [1, 2]×[1, 3]
+	u := Curve{Closed: true, Points: []Point{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}}
+	rng := rand.New(rand.NewSource(21))
+	var sites []Point
+	for len(sites) < 40 {
+		p := Point{X: 3 * rng.Float64(), Y: 3 * rng.Float64()}
+		if u.ContainsPoint(p, NonZero) {
+			sites = append(sites, p)
+		}
+	}
+	cells, err := VoronoiWithRegion([]Curve{u}, sites, VoronoiRegionOptions{})
+	require.NoError(t, err)
+	require.Len(t, cells, len(sites))
+	var sum float64
+	for i, cell := range cells {
+		require.NotEmpty(t, cell)
+		sum += cellsArea(cell)
+		in := false
+		for _, c := range cell {
+			in = in || pointInPolygonOrOnEdge(sites[i], c)
+			for _, p := range c.Points {
+				assert.True(t, u.ContainsPoint(p, NonZero) || u.PointOnEdge(p, 1e-9), "vertex %v", p)
+			}
+		}
+		assert.True(t, in, "site %v outside its cell", sites[i])
+	}
+	assert.InDelta(t, 7, sum, 1e-9)
+}
+
+func TestVoronoiWithRegion_ring(t *testing.T) {
+	outer := Circle{Center: Point{X: 0, Y: 0}, Radius: 4}.ToCurve(64)
+	hole := Circle{Center: Point{X: 0, Y: 0}, Radius: 2}.ToCurve(32)
+	region := []Curve{outer, hole}
+	area := outer.Area() - hole.Area()
+
+	// a single site owns the whole ring, hole included
+	cells, err := VoronoiWithRegion(region, []Point{{X: 3, Y: 0}}, VoronoiRegionOptions{Rule: EvenOdd})
+	require.NoError(t, err)
+	require.Len(t, cells[0], 2)
+	assert.InDelta(t, area, cellsArea(cells[0]), 1e-9)
+
+	var sites []Point
+	for i := 0; i < 16; i++ {
+		a := float64(i) * Tau / 16
+		sites = append(sites, Point{X: 3 * math.Cos(a), Y: 3 * math.Sin(a)})
+	}
+	cells, err = VoronoiWithRegion(region, sites, VoronoiRegionOptions{Rule: EvenOdd})
+	require.NoError(t, err)
+	var sum float64
+	for _, cell := range cells {
+		require.Len(t, cell, 1)
+		assert.InDelta(t, area/16, cellsArea(cell), 1e-9)
+		sum += cellsArea(cell)
+	}
+	assert.InDelta(t, area, sum, 1e-9)
+}
+
+func TestVoronoiWithRegion_outsideSites(t *testing.T) {
+	// two separate squares; a site in one of them owns both
+	a := Rect{X: 0, Y: 0, W: 1, H: 1}.ToCurve()
+	b := Rect{X: 2, Y: 0, W: 1, H: 1}.ToCurve()
+	region := []Curve{a, b}
+	sites := []Point{{X: 0.5, Y: 0.5}, {X: 1.5, Y: 0.5}, {X: 0.5, Y: 0.5}}
+	_, err := VoronoiWithRegion(region, sites, VoronoiRegionOptions{})
+	require.Error(t, err)
+
+	cells, err := VoronoiWithRegion(region, sites, VoronoiRegionOptions{SkipOutside: true})
+	require.NoError(t, err)
+	require.Len(t, cells, 3)
+	assert.Nil(t, cells[1])
+	assert.Len(t, cells[0], 2)
+	assert.InDelta(t, 2, cellsArea(cells[0]), 1e-9)
+	assert.Equal(t, cells[0], cells[2])
+}
+
+func TestVoronoiWithRegion_noEdges(t *testing.T) {
+	// three single points span an extent but bound nothing
+	region := []Curve{{Points: []Point{{0, 0}}}, {Points: []Point{{5, 5}}}, {Points: []Point{{0, 5}}}}
+	for _, opts := range []VoronoiRegionOptions{{}, {SkipOutside: true}} {
+		_, err := VoronoiWithRegion(region, []Point{{X: 1, Y: 4}}, opts)
+		assert.Error(t, err)
+	}
+}
+
+func TestVoronoiWithRegion_agreesWithCurve(t *testing.T) {
+	boundary := triangleCCW(Point{X: 0, Y: 0}, Point{X: 4, Y: 0}, Point{X: 2, Y: 3})
+	sites := []Point{{1, 0.3}, {3, 0.3}, {2, 2}, {2, 1}}
+	want, err := VoronoiWithCurve(boundary, sites)
+	require.NoError(t, err)
+	got, err := VoronoiWithRegion([]Curve{boundary}, sites, VoronoiRegionOptions{})
+	require.NoError(t, err)
+	for i := range sites {
+		require.Len(t, got[i], 1)
+		assert.InDelta(t, want[i].Area(), cellsArea(got[i]), 1e-9)
+	}
+}
+
 // pointInPolygonOrOnEdge uses ray casting; boundary counts as inside for convex cells.
 func pointInPolygonOrOnEdge(p Point, c Curve) bool {
 	if !c.Closed || len(c.Points) < 3 {
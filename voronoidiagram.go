@@ -0,0 +1,194 @@
+package gaul
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VoronoiDiagram is a Voronoi diagram clipped to a rectangle, with the topology that
+// [VoronoiWithRect] discards: the vertices and edges of the diagram, each stored once,
+// and for every site its cell, the edges around it and the neighbouring sites.
+//
+// Sites are referred to by their index in the slice the diagram was built from.
+// Duplicate sites share the cell of their first occurrence, and only that index
+// appears in edges and neighbour lists.
+type VoronoiDiagram struct {
+	Sites    []Point
+	Vertices []Point
+	Edges    []VoronoiEdge
+	// Cells holds the cell of each site
+	Cells []VoronoiCell
+}
+
+// VoronoiEdge is an edge of a [VoronoiDiagram] between vertices A and B. Left is the
+// site whose cell lies to the left going from A to B, and Right the site on the other
+// side, or -1 for edges along the bounds.
+type VoronoiEdge struct {
+	A, B        int
+	Left, Right int
+}
+
+// VoronoiCell is the cell of one site in a [VoronoiDiagram]
+type VoronoiCell struct {
+	Site Point
+	// Polygon is the closed cell, counterclockwise
+	Polygon Curve
+	// Edges holds the indices of the edges around the cell, counterclockwise, the
+	// k-th running from Polygon.Points[k] to the next point
+	Edges []int
+	// Neighbors holds the sites whose cells share an edge with this one, in the
+	// order of Edges
+	Neighbors []int
+}
+
+// Area returns the area of the cell
+func (c VoronoiCell) Area() float64 {
+	return c.Polygon.Area()
+}
+
+// Centroid returns the centroid of the cell
+func (c VoronoiCell) Centroid() Point {
+	return c.Polygon.Centroid()
+}
+
+// NewVoronoiDiagram computes the Voronoi diagram of sites clipped to bounds with
+// Fortune's algorithm, as [VoronoiWithRect] does. Sites must lie inside or on bounds.
+// Vertices are joined where the sweep computes exactly the same point, so edges that
+// meet at a Voronoi vertex share its index.
+func NewVoronoiDiagram(bounds Rect, sites []Point) (*VoronoiDiagram, error) {
+	if bounds.W <= 0 || bounds.H <= 0 {
+		return nil, errors.New("gaul NewVoronoiDiagram: bounds width and height must be positive")
+	}
+	for i, p := range sites {
+		if !bounds.ContainsPoint(p) {
+			return nil, fmt.Errorf("gaul NewVoronoiDiagram: site %d is not inside bounds", i)
+		}
+	}
+	d := &VoronoiDiagram{Sites: sites, Cells: make([]VoronoiCell, len(sites))}
+	if len(sites) == 0 {
+		return d, nil
+	}
+	first := make(map[Point]int, len(sites))
+	for i, p := range sites {
+		if _, ok := first[p]; !ok {
+			first[p] = i
+		}
+	}
+	bbox := rectToBBox(bounds)
+	vertex := make(map[Point]int)
+	addVertex := func(p Point) int {
+		if i, ok := vertex[p]; ok {
+			return i
+		}
+		vertex[p] = len(d.Vertices)
+		d.Vertices = append(d.Vertices, p)
+		return len(d.Vertices) - 1
+	}
+
+	unique := uniqueSitesFortune(sites)
+	if len(unique) == 1 {
+		// no bisectors: the cell is the whole rectangle
+		pts := ensurePolygonCCW(bounds.ToCurve().Points)
+		cell := VoronoiCell{Site: unique[0], Polygon: Curve{Points: pts, Closed: true}}
+		for k := range pts {
+			cell.Edges = append(cell.Edges, len(d.Edges))
+			d.Edges = append(d.Edges, VoronoiEdge{A: addVertex(pts[k]), B: addVertex(pts[(k+1)%len(pts)]), Left: 0, Right: -1})
+		}
+		for i := range d.Cells {
+			d.Cells[i] = cell
+		}
+		return d, nil
+	}
+
+	// the sweep works with Y down: its edges run from Va to Vb with the left cell on
+	// the right when Y is up, and its halfedges go clockwise around each cell
+	fd := computeFortuneDiagram(unique, bbox, true)
+	edgeIndex := make(map[*Edge]int, len(fd.Edges))
+	for _, e := range fd.Edges {
+		va, vb := e.Va.Point, e.Vb.Point
+		if va == vb {
+			continue
+		}
+		ve := VoronoiEdge{A: addVertex(vb), B: addVertex(va), Left: first[e.LeftCell.Site], Right: -1}
+		if e.RightCell != nil {
+			ve.Right = first[e.RightCell.Site]
+		}
+		edgeIndex[e] = len(d.Edges)
+		d.Edges = append(d.Edges, ve)
+	}
+
+	bySite := make(map[Point]VoronoiCell, len(fd.Cells))
+	for _, c := range fd.Cells {
+		self := first[c.Site]
+		cell := VoronoiCell{Site: c.Site, Polygon: Curve{Closed: true}}
+		seen := make(map[int]bool)
+		for k := len(c.Halfedges) - 1; k >= 0; k-- {
+			e, ok := edgeIndex[c.Halfedges[k].Edge]
+			if !ok {
+				continue
+			}
+			ve := d.Edges[e]
+			start, other := ve.A, ve.Right
+			if ve.Left != self {
+				start, other = ve.B, ve.Left
+			}
+			cell.Edges = append(cell.Edges, e)
+			cell.Polygon.Points = append(cell.Polygon.Points, d.Vertices[start])
+			if other >= 0 && !seen[other] {
+				seen[other] = true
+				cell.Neighbors = append(cell.Neighbors, other)
+			}
+		}
+		bySite[c.Site] = cell
+	}
+	for i, p := range sites {
+		d.Cells[i] = bySite[p]
+	}
+	return d, nil
+}
+
+// Edge returns edge e as a line from its vertex A to B
+func (d *VoronoiDiagram) Edge(e int) Line {
+	return Line{P: d.Vertices[d.Edges[e].A], Q: d.Vertices[d.Edges[e].B]}
+}
+
+// Neighbors returns the sites whose cells share an edge with the cell of site i
+func (d *VoronoiDiagram) Neighbors(i int) []int {
+	return d.Cells[i].Neighbors
+}
+
+// SharedEdge returns the edge between the cells of sites i and j, and false if they
+// aren't neighbours
+func (d *VoronoiDiagram) SharedEdge(i, j int) (Line, bool) {
+	a, b := d.Cells[i].Site, d.Cells[j].Site
+	for _, e := range d.Cells[i].Edges {
+		ve := d.Edges[e]
+		if ve.Right < 0 {
+			continue
+		}
+		l, r := d.Sites[ve.Left], d.Sites[ve.Right]
+		if (l == a && r == b) || (l == b && r == a) {
+			return d.Edge(e), true
+		}
+	}
+	return Line{}, false
+}
+
+// ToCurves returns the cell of each site, as [VoronoiWithRect] does
+func (d *VoronoiDiagram) ToCurves() []Curve {
+	out := make([]Curve, len(d.Cells))
+	for i, c := range d.Cells {
+		out[i] = c.Polygon
+	}
+	return out
+}
+
+// ToLines returns the edges of the diagram, each once, including those along the
+// bounds
+func (d *VoronoiDiagram) ToLines() []Line {
+	out := make([]Line, len(d.Edges))
+	for e := range d.Edges {
+		out[e] = d.Edge(e)
+	}
+	return out
+}
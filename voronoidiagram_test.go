@@ -0,0 +1,111 @@
+package gaul
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVoronoiDiagram(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	bounds := Rect{X: -1, Y: 2, W: 8, H: 5}
+	var sites []Point
+	for i := 0; i < 150; i++ {
+		sites = append(sites, Point{X: bounds.X + bounds.W*rng.Float64(), Y: bounds.Y + bounds.H*rng.Float64()})
+	}
+	d, err := NewVoronoiDiagram(bounds, sites)
+	require.NoError(t, err)
+	require.Len(t, d.Cells, len(sites))
+	curves, err := VoronoiWithRect(bounds, sites)
+	require.NoError(t, err)
+
+	// the cells tile the bounds: V - E + F = 1
+	assert.Equal(t, 1, len(d.Vertices)-len(d.Edges)+len(d.Cells))
+	var sum float64
+	for i, c := range d.Cells {
+		sum += c.Area()
+		assert.InDelta(t, curves[i].Area(), c.Area(), 1e-9)
+		assert.Greater(t, c.Polygon.SignedArea(), 0.0)
+		assert.True(t, c.Polygon.ContainsPoint(c.Centroid(), NonZero))
+		require.Len(t, c.Edges, len(c.Polygon.Points))
+		for k, e := range c.Edges {
+			l := d.Edge(e)
+			p, q := c.Polygon.Points[k], c.Polygon.Points[(k+1)%len(c.Edges)]
+			assert.True(t, (l.P == p && l.Q == q) || (l.P == q && l.Q == p))
+		}
+		for _, n := range c.Neighbors {
+			assert.Contains(t, d.Neighbors(n), i)
+			l, ok := d.SharedEdge(i, n)
+			require.True(t, ok)
+			// the shared edge lies on the bisector
+			for _, p := range []Point{l.P, l.Q} {
+				assert.InDelta(t, Distance(p, sites[i]), Distance(p, sites[n]), 1e-9)
+			}
+		}
+	}
+	assert.InDelta(t, bounds.W*bounds.H, sum, 1e-9)
+
+	for _, e := range d.Edges {
+		a, b := d.Vertices[e.A], d.Vertices[e.B]
+		assert.GreaterOrEqual(t, orient2(a, b, sites[e.Left]), 0.0)
+		if e.Right >= 0 {
+			assert.Less(t, orient2(a, b, sites[e.Right]), 0.0)
+		} else {
+			assert.True(t, a.X == b.X || a.Y == b.Y, "border edge %v %v", a, b)
+		}
+	}
+	// vertices cut at the bounds lie exactly on them
+	for _, v := range d.Vertices {
+		for _, side := range []float64{v.X - bounds.X, bounds.X + bounds.W - v.X, v.Y - bounds.Y, bounds.Y + bounds.H - v.Y} {
+			assert.True(t, side == 0 || side > 1e-9, "vertex %v", v)
+		}
+	}
+	assert.Len(t, d.ToLines(), len(d.Edges))
+	_, ok := d.SharedEdge(0, 0)
+	assert.False(t, ok)
+}
+
+func TestNewVoronoiDiagram_grid(t *testing.T) {
+	// a 3x3 grid: the middle cell has four neighbours and corner cells two
+	var sites []Point
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			sites = append(sites, Point{X: float64(i) + 0.5, Y: float64(j) + 0.5})
+		}
+	}
+	sites = append(sites, Point{X: 1.5, Y: 1.5})
+	d, err := NewVoronoiDiagram(Rect{X: 0, Y: 0, W: 3, H: 3}, sites)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{1, 3, 5, 7}, d.Neighbors(4))
+	assert.ElementsMatch(t, []int{1, 3}, d.Neighbors(0))
+	assert.Equal(t, d.Cells[4], d.Cells[9])
+	l, ok := d.SharedEdge(4, 5)
+	require.True(t, ok)
+	assert.InDelta(t, 2, l.P.X, 1e-9)
+	assert.InDelta(t, 1, l.Length(), 1e-9)
+	assert.Len(t, d.Vertices, 16)
+	assert.Len(t, d.Edges, 24)
+	for _, c := range d.Cells {
+		assert.InDelta(t, 1, c.Area(), 1e-9)
+		assert.InDelta(t, c.Site.X, c.Centroid().X, 1e-9)
+		assert.InDelta(t, c.Site.Y, c.Centroid().Y, 1e-9)
+	}
+}
+
+func TestNewVoronoiDiagram_small(t *testing.T) {
+	bounds := Rect{X: 0, Y: 0, W: 2, H: 1}
+	d, err := NewVoronoiDiagram(bounds, []Point{{X: 1, Y: 0.5}, {X: 1, Y: 0.5}})
+	require.NoError(t, err)
+	require.Len(t, d.Cells, 2)
+	assert.Len(t, d.Edges, 4)
+	assert.InDelta(t, 2, d.Cells[1].Area(), 1e-12)
+	assert.Empty(t, d.Neighbors(0))
+
+	d, err = NewVoronoiDiagram(bounds, nil)
+	require.NoError(t, err)
+	assert.Empty(t, d.Edges)
+	_, err = NewVoronoiDiagram(bounds, []Point{{X: 3, Y: 0}})
+	assert.Error(t, err)
+}